@@ -4,6 +4,7 @@ require (
 	github.com/eclipse/paho.mqtt.golang v1.4.1
 	github.com/google/uuid v1.3.0
 	github.com/pkg/errors v0.9.1
+	github.com/shopspring/decimal v1.4.0
 	github.com/stretchr/testify v1.3.0
 	golang.org/x/oauth2 v0.0.0-20221014153046-6fdb5e3db783
 	quantfu.com/webull/client v0.0.0-00010101000000-000000000000
@@ -0,0 +1,260 @@
+// Package pricing computes option prices, greeks and implied volatility with
+// Black-Scholes-Merton for European exercise and the Barone-Adesi and Whaley
+// approximation for American exercise.
+//
+// Greeks follow the conventions of Webull quotes: theta is per calendar day,
+// vega and rho are per percentage point move in volatility and rate.
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// DaysPerYear converts calendar days to years.
+	DaysPerYear = 365.0
+
+	minVolatility = 1e-6
+	maxVolatility = 10.0
+)
+
+// Inputs describe an option and its market.
+type Inputs struct {
+	Call   bool
+	Spot   float64
+	Strike float64
+	// Years to expiry, see YearsToExpiry.
+	Years float64
+	// Rate is the continuously compounded risk-free rate, e.g. 0.05 for 5%.
+	Rate float64
+	// DividendYield is the continuous dividend yield of the underlying.
+	DividendYield float64
+	// Volatility is the annualised volatility, e.g. 0.25 for 25%.
+	Volatility float64
+}
+
+// Greeks is a theoretical option price and its sensitivities.
+type Greeks struct {
+	Price float64
+	Delta float64
+	Gamma float64
+	// Theta is the change in price per calendar day.
+	Theta float64
+	// Vega is the change in price per percentage point of volatility.
+	Vega float64
+	// Rho is the change in price per percentage point of the rate.
+	Rho float64
+}
+
+// YearsToExpiry is the time from `now` until the market close on `expiry`'s
+// date, US options expiring at 16:00 New York time.
+func YearsToExpiry(now, expiry time.Time) float64 {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.FixedZone("EST", -5*60*60)
+	}
+	y, m, d := expiry.Date()
+	closeTime := time.Date(y, m, d, 16, 0, 0, 0, loc)
+	years := closeTime.Sub(now).Hours() / 24 / DaysPerYear
+	return math.Max(years, 0)
+}
+
+// BlackScholes prices a European option with the Black-Scholes-Merton model.
+func BlackScholes(in Inputs) Greeks {
+	if in.Years <= 0 || in.Volatility <= 0 || in.Spot <= 0 || in.Strike <= 0 {
+		return expired(in)
+	}
+	var (
+		s, k, t, r, q, v = in.Spot, in.Strike, in.Years, in.Rate, in.DividendYield, in.Volatility
+		sqrtT            = math.Sqrt(t)
+		d1               = (math.Log(s/k) + (r-q+v*v/2)*t) / (v * sqrtT)
+		d2               = d1 - v*sqrtT
+		dq, dr           = math.Exp(-q * t), math.Exp(-r * t)
+		g                Greeks
+	)
+	g.Gamma = dq * normPDF(d1) / (s * v * sqrtT)
+	g.Vega = s * dq * normPDF(d1) * sqrtT / 100
+	decay := -s * dq * normPDF(d1) * v / (2 * sqrtT)
+	if in.Call {
+		g.Price = s*dq*normCDF(d1) - k*dr*normCDF(d2)
+		g.Delta = dq * normCDF(d1)
+		g.Theta = (decay - r*k*dr*normCDF(d2) + q*s*dq*normCDF(d1)) / DaysPerYear
+		g.Rho = k * t * dr * normCDF(d2) / 100
+	} else {
+		g.Price = k*dr*normCDF(-d2) - s*dq*normCDF(-d1)
+		g.Delta = -dq * normCDF(-d1)
+		g.Theta = (decay + r*k*dr*normCDF(-d2) - q*s*dq*normCDF(-d1)) / DaysPerYear
+		g.Rho = -k * t * dr * normCDF(-d2) / 100
+	}
+	return g
+}
+
+// American prices an American option with the Barone-Adesi and Whaley
+// approximation. Its greeks are taken by finite differences.
+func American(in Inputs) Greeks {
+	if in.Years <= 0 || in.Volatility <= 0 || in.Spot <= 0 || in.Strike <= 0 {
+		return expired(in)
+	}
+	price := americanPrice(in)
+	g := Greeks{Price: price}
+
+	h := in.Spot * 1e-3
+	up, down := in, in
+	up.Spot, down.Spot = in.Spot+h, in.Spot-h
+	pu, pd := americanPrice(up), americanPrice(down)
+	g.Delta = (pu - pd) / (2 * h)
+	g.Gamma = (pu - 2*price + pd) / (h * h)
+
+	later := in
+	later.Years = math.Max(in.Years-1/DaysPerYear, 0)
+	g.Theta = americanPrice(later) - price
+
+	bumped := in
+	bumped.Volatility += 0.01
+	g.Vega = americanPrice(bumped) - price
+
+	bumped = in
+	bumped.Rate += 0.01
+	g.Rho = americanPrice(bumped) - price
+	return g
+}
+
+// ImpliedVolatility solves for the volatility at which the European price of
+// `in` equals `price`.
+func ImpliedVolatility(price float64, in Inputs) (float64, error) {
+	return impliedVolatility(price, in, func(in Inputs) float64 { return BlackScholes(in).Price })
+}
+
+// AmericanImpliedVolatility solves for the volatility at which the American
+// price of `in` equals `price`.
+func AmericanImpliedVolatility(price float64, in Inputs) (float64, error) {
+	return impliedVolatility(price, in, americanPrice)
+}
+
+func impliedVolatility(price float64, in Inputs, model func(Inputs) float64) (float64, error) {
+	if in.Years <= 0 {
+		return 0, fmt.Errorf("option has expired")
+	}
+	if in.Spot <= 0 || in.Strike <= 0 {
+		return 0, fmt.Errorf("invalid spot %v or strike %v", in.Spot, in.Strike)
+	}
+	lo, hi := in, in
+	lo.Volatility, hi.Volatility = minVolatility, maxVolatility
+	low, high := model(lo), model(hi)
+	if price < low || price > high {
+		return 0, fmt.Errorf("price %v is outside the range %v to %v of possible prices", price, low, high)
+	}
+	// bisection is slow but cannot diverge
+	a, b := minVolatility, maxVolatility
+	for i := 0; i < 100 && b-a > 1e-10; i++ {
+		mid := in
+		mid.Volatility = (a + b) / 2
+		if model(mid) < price {
+			a = mid.Volatility
+		} else {
+			b = mid.Volatility
+		}
+	}
+	return (a + b) / 2, nil
+}
+
+// americanPrice is the Barone-Adesi and Whaley price of `in`.
+func americanPrice(in Inputs) float64 {
+	if in.Years <= 0 || in.Volatility <= 0 {
+		return expired(in).Price
+	}
+	european := BlackScholes(in).Price
+	var (
+		s, k, t, r, q, v = in.Spot, in.Strike, in.Years, in.Rate, in.DividendYield, in.Volatility
+		b                = r - q
+	)
+	// early exercise is never worth it for calls without a dividend, or for
+	// puts without a positive rate
+	if (in.Call && q <= 0) || (!in.Call && r <= 0) {
+		return european
+	}
+	var (
+		n     = 2 * b / (v * v)
+		m     = 2 * r / (v * v)
+		kk    = 1 - math.Exp(-r*t)
+		carry = math.Exp((b - r) * t)
+	)
+	d1 := func(spot float64) float64 {
+		return (math.Log(spot/k) + (b+v*v/2)*t) / (v * math.Sqrt(t))
+	}
+	at := func(spot float64) float64 {
+		x := in
+		x.Spot = spot
+		return BlackScholes(x).Price
+	}
+
+	if in.Call {
+		q2 := (-(n - 1) + math.Sqrt((n-1)*(n-1)+4*m/kk)) / 2
+		f := func(spot float64) float64 {
+			return spot - k - at(spot) - (1-carry*normCDF(d1(spot)))*spot/q2
+		}
+		hi := k
+		for i := 0; i < 100 && f(hi) < 0; i++ {
+			hi *= 2
+		}
+		critical := bisect(f, k, hi)
+		if s >= critical {
+			return s - k
+		}
+		a2 := critical / q2 * (1 - carry*normCDF(d1(critical)))
+		return european + a2*math.Pow(s/critical, q2)
+	}
+
+	q1 := (-(n - 1) - math.Sqrt((n-1)*(n-1)+4*m/kk)) / 2
+	f := func(spot float64) float64 {
+		return k - spot - at(spot) + (1-carry*normCDF(-d1(spot)))*spot/q1
+	}
+	critical := bisect(f, k*1e-6, k)
+	if s <= critical {
+		return k - s
+	}
+	a1 := -critical / q1 * (1 - carry*normCDF(-d1(critical)))
+	return math.Max(european+a1*math.Pow(s/critical, q1), k-s)
+}
+
+// bisect finds a root of `f` between `lo` and `hi`.
+func bisect(f func(float64) float64, lo, hi float64) float64 {
+	flo := f(lo)
+	for i := 0; i < 200 && hi-lo > 1e-9*hi; i++ {
+		mid := (lo + hi) / 2
+		fmid := f(mid)
+		if (fmid < 0) == (flo < 0) {
+			lo, flo = mid, fmid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// expired values an option at expiry or with no volatility as its discounted
+// intrinsic value.
+func expired(in Inputs) Greeks {
+	forward := in.Spot * math.Exp((in.Rate-in.DividendYield)*math.Max(in.Years, 0))
+	discount := math.Exp(-in.Rate * math.Max(in.Years, 0))
+	g := Greeks{}
+	switch {
+	case in.Call && forward > in.Strike:
+		g.Price = discount * (forward - in.Strike)
+		g.Delta = 1
+	case !in.Call && forward < in.Strike:
+		g.Price = discount * (in.Strike - forward)
+		g.Delta = -1
+	}
+	return g
+}
+
+func normCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+func normPDF(x float64) float64 {
+	return math.Exp(-x*x/2) / math.Sqrt(2*math.Pi)
+}
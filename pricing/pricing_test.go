@@ -0,0 +1,143 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlackScholes(t *testing.T) {
+	asrt := assert.New(t)
+	// Hull, Options, Futures and Other Derivatives, example 15.6
+	in := Inputs{Call: true, Spot: 42, Strike: 40, Years: 0.5, Rate: 0.1, Volatility: 0.2}
+	call := BlackScholes(in)
+	asrt.InDelta(4.76, call.Price, 0.005)
+	in.Call = false
+	put := BlackScholes(in)
+	asrt.InDelta(0.81, put.Price, 0.005)
+
+	// put-call parity with a dividend yield
+	in = Inputs{Call: true, Spot: 100, Strike: 95, Years: 0.75, Rate: 0.04, DividendYield: 0.02, Volatility: 0.3}
+	call = BlackScholes(in)
+	in.Call = false
+	put = BlackScholes(in)
+	parity := 100*math.Exp(-0.02*0.75) - 95*math.Exp(-0.04*0.75)
+	asrt.InDelta(parity, call.Price-put.Price, 1e-9)
+	asrt.InDelta(math.Exp(-0.02*0.75), call.Delta-put.Delta, 1e-9)
+
+	// analytic greeks agree with finite differences
+	in.Call = true
+	bump := func(f func(*Inputs), h float64) float64 {
+		up, down := in, in
+		f(&up)
+		return (BlackScholes(up).Price - BlackScholes(down).Price) / h
+	}
+	asrt.InDelta(call.Delta, bump(func(x *Inputs) { x.Spot += 1e-4 }, 1e-4), 1e-4)
+	asrt.InDelta(call.Vega, bump(func(x *Inputs) { x.Volatility += 1e-4 }, 1e-2), 1e-4)
+	asrt.InDelta(call.Rho, bump(func(x *Inputs) { x.Rate += 1e-4 }, 1e-2), 1e-4)
+	asrt.InDelta(call.Theta, bump(func(x *Inputs) { x.Years -= 1e-4 }, 1e-4*DaysPerYear), 1e-4)
+	asrt.InDelta(call.Gamma, (BlackScholes(Inputs{Call: true, Spot: 100.01, Strike: 95, Years: 0.75, Rate: 0.04, DividendYield: 0.02, Volatility: 0.3}).Delta-call.Delta)/0.01, 1e-4)
+
+	expired := BlackScholes(Inputs{Call: false, Spot: 90, Strike: 100})
+	asrt.Equal(10.0, expired.Price)
+	asrt.Equal(-1.0, expired.Delta)
+}
+
+func TestAmerican(t *testing.T) {
+	asrt := assert.New(t)
+	// without dividends an American call is never exercised early
+	in := Inputs{Call: true, Spot: 100, Strike: 100, Years: 0.5, Rate: 0.05, Volatility: 0.25}
+	asrt.InDelta(BlackScholes(in).Price, American(in).Price, 1e-9)
+
+	// Haug, The Complete Guide to Option Pricing Formulas, table 3-1
+	in = Inputs{Call: false, Spot: 90, Strike: 100, Years: 0.1, Rate: 0.1, DividendYield: 0.1, Volatility: 0.15}
+	asrt.InDelta(10.0, American(in).Price, 0.01)
+	in = Inputs{Call: true, Spot: 110, Strike: 100, Years: 0.1, Rate: 0.1, DividendYield: 0.1, Volatility: 0.15}
+	asrt.InDelta(10.0, American(in).Price, 0.01)
+
+	// close to a binomial tree away from the exercise boundary
+	for _, in := range []Inputs{
+		{Call: false, Spot: 100, Strike: 100, Years: 0.5, Rate: 0.1, DividendYield: 0.1, Volatility: 0.35},
+		{Call: false, Spot: 95, Strike: 100, Years: 1, Rate: 0.06, Volatility: 0.25},
+		{Call: true, Spot: 105, Strike: 100, Years: 0.75, Rate: 0.03, DividendYield: 0.08, Volatility: 0.3},
+	} {
+		tree := binomialAmerican(in, 2000)
+		asrt.InDelta(tree, American(in).Price, tree*0.01, "%+v", in)
+	}
+
+	// an American put is worth at least its European counterpart and intrinsic value
+	in = Inputs{Call: false, Spot: 80, Strike: 100, Years: 1, Rate: 0.08, Volatility: 0.2}
+	american := American(in)
+	asrt.True(american.Price >= BlackScholes(in).Price)
+	asrt.True(american.Price >= 20-1e-9)
+	asrt.True(american.Delta < 0 && american.Delta >= -1)
+
+	in.Spot = 100
+	american = American(in)
+	asrt.True(american.Price > BlackScholes(in).Price)
+	asrt.True(american.Gamma > 0)
+	asrt.True(american.Vega > 0)
+	asrt.True(american.Theta < 0)
+}
+
+func TestImpliedVolatility(t *testing.T) {
+	asrt := assert.New(t)
+	in := Inputs{Call: false, Spot: 150, Strike: 145, Years: 30 / DaysPerYear, Rate: 0.045, DividendYield: 0.006, Volatility: 0.32}
+	price := BlackScholes(in).Price
+	in.Volatility = 0
+	iv, err := ImpliedVolatility(price, in)
+	asrt.Empty(err)
+	asrt.InDelta(0.32, iv, 1e-6)
+
+	in.Volatility = 0.45
+	price = American(in).Price
+	in.Volatility = 0
+	iv, err = AmericanImpliedVolatility(price, in)
+	asrt.Empty(err)
+	asrt.InDelta(0.45, iv, 1e-6)
+
+	_, err = ImpliedVolatility(200, in)
+	asrt.Error(err, "above the spot")
+	in.Years = 0
+	_, err = ImpliedVolatility(1, in)
+	asrt.Error(err)
+}
+
+func TestYearsToExpiry(t *testing.T) {
+	asrt := assert.New(t)
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		loc = time.FixedZone("EST", -5*60*60)
+	}
+	now := time.Date(2023, 3, 16, 16, 0, 0, 0, loc)
+	asrt.InDelta(1/DaysPerYear, YearsToExpiry(now, time.Date(2023, 3, 17, 0, 0, 0, 0, time.UTC)), 1e-9)
+	asrt.Equal(0.0, YearsToExpiry(now, time.Date(2023, 3, 10, 0, 0, 0, 0, time.UTC)))
+}
+
+// binomialAmerican prices an American option on a Cox-Ross-Rubinstein tree.
+func binomialAmerican(in Inputs, steps int) float64 {
+	dt := in.Years / float64(steps)
+	u := math.Exp(in.Volatility * math.Sqrt(dt))
+	d := 1 / u
+	p := (math.Exp((in.Rate-in.DividendYield)*dt) - d) / (u - d)
+	disc := math.Exp(-in.Rate * dt)
+	payoff := func(s float64) float64 {
+		if in.Call {
+			return math.Max(s-in.Strike, 0)
+		}
+		return math.Max(in.Strike-s, 0)
+	}
+	values := make([]float64, steps+1)
+	for i := range values {
+		values[i] = payoff(in.Spot * math.Pow(u, float64(steps-i)) * math.Pow(d, float64(i)))
+	}
+	for step := steps - 1; step >= 0; step-- {
+		for i := 0; i <= step; i++ {
+			s := in.Spot * math.Pow(u, float64(step-i)) * math.Pow(d, float64(i))
+			values[i] = math.Max(disc*(p*values[i]+(1-p)*values[i+1]), payoff(s))
+		}
+	}
+	return values[0]
+}
@@ -0,0 +1,176 @@
+package webull
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	model "quantfu.com/webull/openapi"
+)
+
+// RegisteredAccount is a brokerage account loaded by an AccountRegistry.
+type RegisteredAccount struct {
+	ID              int64
+	BrokerAccountID string
+	Kind            AccountKind
+	// Type is the account type as reported by Webull, e.g. "MARGIN".
+	Type     string
+	Nickname string
+	// Rzone is the region the account lives in, sent as the lzone header.
+	Rzone string
+}
+
+// AccountRegistry holds every account returned by GetAccountsV5 and hands out
+// a sub-client per account with the account's lzone header applied, so several
+// accounts can be traded at once.
+type AccountRegistry struct {
+	client *Client
+
+	mu       sync.Mutex
+	accounts []RegisteredAccount
+	clients  map[int64]*Client
+}
+
+// NewAccountRegistry is a constructor for an AccountRegistry loaded from `c`.
+func NewAccountRegistry(c *Client) (*AccountRegistry, error) {
+	r := &AccountRegistry{client: c}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload fetches the account list again and drops cached sub-clients.
+func (r *AccountRegistry) Reload() error {
+	res, err := r.client.GetAccountsV5()
+	if err != nil {
+		return err
+	}
+	accounts := make([]RegisteredAccount, 0, len(res.AccountList))
+	for _, acc := range res.AccountList {
+		accounts = append(accounts, newRegisteredAccount(acc))
+	}
+	r.mu.Lock()
+	r.accounts = accounts
+	r.clients = nil
+	r.mu.Unlock()
+	return nil
+}
+
+// Accounts lists every registered account.
+func (r *AccountRegistry) Accounts() []RegisteredAccount {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]RegisteredAccount, len(r.accounts))
+	copy(out, r.accounts)
+	return out
+}
+
+// ByID returns account `accountID`.
+func (r *AccountRegistry) ByID(accountID int64) (RegisteredAccount, error) {
+	return r.find(func(a RegisteredAccount) bool { return a.ID == accountID }, "id %d", accountID)
+}
+
+// ByKind returns the first account of `kind`.
+func (r *AccountRegistry) ByKind(kind AccountKind) (RegisteredAccount, error) {
+	return r.find(func(a RegisteredAccount) bool { return a.Kind == kind }, "kind %s", kind)
+}
+
+// ByNickname returns the account named `nickname`, ignoring case.
+func (r *AccountRegistry) ByNickname(nickname string) (RegisteredAccount, error) {
+	return r.find(func(a RegisteredAccount) bool { return strings.EqualFold(a.Nickname, nickname) }, "nickname %q", nickname)
+}
+
+// Select returns the account matching `query` as an ID, nickname, account
+// type or kind, in that order.
+func (r *AccountRegistry) Select(query string) (RegisteredAccount, error) {
+	if id, err := strconv.ParseInt(query, 10, 64); err == nil {
+		if acc, err := r.ByID(id); err == nil {
+			return acc, nil
+		}
+	}
+	if acc, err := r.ByNickname(query); err == nil {
+		return acc, nil
+	}
+	if acc, err := r.find(func(a RegisteredAccount) bool { return strings.EqualFold(a.Type, query) }, ""); err == nil {
+		return acc, nil
+	}
+	if acc, err := r.ByKind(AccountKind(strings.ToUpper(query))); err == nil {
+		return acc, nil
+	}
+	return RegisteredAccount{}, fmt.Errorf("no account matches %q", query)
+}
+
+// Client returns the sub-client for account `accountID`. It shares the parent's
+// session, risk manager and dry-run settings, with the account's lzone header
+// applied.
+func (r *AccountRegistry) Client(accountID int64) (*Client, error) {
+	acc, err := r.ByID(accountID)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if sub, ok := r.clients[accountID]; ok {
+		return sub, nil
+	}
+	if r.clients == nil {
+		r.clients = make(map[int64]*Client)
+	}
+	sub := r.client.forAccount(acc)
+	r.clients[accountID] = sub
+	return sub, nil
+}
+
+func (r *AccountRegistry) find(match func(RegisteredAccount) bool, format string, args ...interface{}) (RegisteredAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, acc := range r.accounts {
+		if match(acc) {
+			return acc, nil
+		}
+	}
+	return RegisteredAccount{}, fmt.Errorf("no account with "+format, args...)
+}
+
+// forAccount returns a sub-client with the session headers of `acc`. It reads
+// its tokens from `c` on every request, so logging in or refreshing on the
+// parent applies to it. Streams and websocket callbacks are not shared.
+func (c *Client) forAccount(acc RegisteredAccount) *Client {
+	sub := &Client{
+		Username:       c.Username,
+		HashedPassword: c.HashedPassword,
+		AccountType:    c.AccountType,
+		MFA:            c.MFA,
+		UUID:           c.UUID,
+		DeviceName:     c.DeviceName,
+		DeviceID:       c.DeviceID,
+		httpClient:     c.httpClient,
+		parent:         c,
+		MdProvider:     c.MdProvider,
+		PaperAccountID: c.PaperAccountID,
+		Risk:           c.Risk,
+		DryRun:         c.DryRun,
+		RateLimit:      c.RateLimit,
+	}
+	sub.sessionHeaders = make(map[string]string, len(c.sessionHeaders)+1)
+	for k, v := range c.sessionHeaders {
+		sub.sessionHeaders[k] = v
+	}
+	if acc.Rzone != "" {
+		sub.sessionHeaders[HeaderLzone] = acc.Rzone
+	}
+	return sub
+}
+
+func newRegisteredAccount(acc model.AccountV5) RegisteredAccount {
+	return RegisteredAccount{
+		ID:              acc.GetSecAccountId(),
+		BrokerAccountID: acc.GetBrokerAccountId(),
+		Kind:            accountKind(acc.GetAccountTypeName(), acc.GetAccountType()),
+		Type:            acc.GetAccountType(),
+		Nickname:        acc.GetNickName(),
+		Rzone:           acc.GetRzone(),
+	}
+}
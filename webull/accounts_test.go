@@ -0,0 +1,155 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+func TestAccountRegistrySelect(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	c.AddSessionHeader("x-test", "1")
+
+	r := &AccountRegistry{client: c}
+	r.accounts = []RegisteredAccount{
+		newRegisteredAccount(model.AccountV5{
+			SecAccountId: model.PtrInt64(111), AccountType: model.PtrString("CASH"),
+			AccountTypeName: model.PtrString("Individual Cash"), NickName: model.PtrString("Swing"),
+			Rzone: model.PtrString("dc_core_r001"),
+		}),
+		newRegisteredAccount(model.AccountV5{
+			SecAccountId: model.PtrInt64(222), AccountType: model.PtrString("MARGIN"),
+			AccountTypeName: model.PtrString("Roth IRA"), Rzone: model.PtrString("dc_core_r002"),
+		}),
+	}
+
+	acc, err := r.Select("swing")
+	asrt.Empty(err)
+	asrt.Equal(int64(111), acc.ID)
+	asrt.Equal(AccountKindCash, acc.Kind)
+
+	acc, err = r.Select("222")
+	asrt.Empty(err)
+	asrt.Equal(AccountKindIRA, acc.Kind)
+
+	acc, err = r.ByKind(AccountKindIRA)
+	asrt.Empty(err)
+	asrt.Equal(int64(222), acc.ID)
+
+	_, err = r.Select("margin")
+	asrt.Empty(err)
+	_, err = r.Select("joint")
+	asrt.Error(err)
+
+	cash, err := r.Client(111)
+	asrt.Empty(err)
+	ira, err := r.Client(222)
+	asrt.Empty(err)
+	asrt.Equal("dc_core_r001", cash.sessionHeaders[HeaderLzone])
+	asrt.Equal("dc_core_r002", ira.sessionHeaders[HeaderLzone])
+	asrt.Equal("1", ira.sessionHeaders["x-test"])
+	asrt.Empty(c.sessionHeaders[HeaderLzone])
+	again, _ := r.Client(111)
+	asrt.True(again == cash)
+}
+
+func TestAccountRegistrySubClientTokens(t *testing.T) {
+	asrt := assert.New(t)
+	var seen []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		seen = append(seen, req.Header.Get(HeaderKeyAccessToken)+"/"+req.Header.Get(HeaderKeyTradeToken)+"/"+req.Header.Get(HeaderLzone))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	c.AccessToken, c.TradeToken = "access-1", "trade-1"
+	r := &AccountRegistry{client: c}
+	r.accounts = []RegisteredAccount{{ID: 111, Rzone: "dc_core_r001"}}
+	sub, err := r.Client(111)
+	asrt.Empty(err)
+
+	_, err = sub.GetAccountV5()
+	asrt.Empty(err)
+	// the parent refreshes its tokens; the cached sub-client follows
+	c.AccessToken, c.TradeToken = "access-2", "trade-2"
+	c.AccessTokenExpiration = time.Now().Add(2 * time.Hour)
+	_, err = sub.GetAccountV5()
+	asrt.Empty(err)
+	asrt.Equal([]string{"access-1/trade-1/dc_core_r001", "access-2/trade-2/dc_core_r001"}, seen)
+	asrt.Empty(sub.AccessToken, "tokens are read from the parent, not copied")
+}
+
+func TestAccountRegistrySubClientsConcurrent(t *testing.T) {
+	asrt := assert.New(t)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	c.RegisterCallback(false, func(context.Context, Topic, interface{}) error { return nil }, "102")
+	r := &AccountRegistry{client: c}
+	r.accounts = []RegisteredAccount{{ID: 111, Rzone: "dc_core_r001"}, {ID: 222, Rzone: "dc_core_r002"}}
+	cash, err := r.Client(111)
+	asrt.Empty(err)
+	ira, err := r.Client(222)
+	asrt.Empty(err)
+	asrt.Empty(cash.WebsocketCallbacks)
+
+	// both sub-clients trade while the parent refreshes; run with -race
+	var wg sync.WaitGroup
+	for _, sub := range []*Client{cash, ira, cash, ira} {
+		wg.Add(1)
+		go func(sub *Client) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				_, err := sub.GetAccountV5()
+				asrt.Empty(err)
+			}
+		}(sub)
+	}
+	for i := 0; i < 10; i++ {
+		c.tokenMu.Lock()
+		c.AccessToken = fmt.Sprintf("access-%d", i)
+		c.tokenMu.Unlock()
+	}
+	wg.Wait()
+}
+
+func TestNewAccountRegistry(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	err = c.Login(Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	err = c.TradeLogin(Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		AccountType: model.AccountType(2),
+		TradePIN:    os.Getenv("WEBULL_PIN"),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+
+	r, err := NewAccountRegistry(c)
+	asrt.Empty(err)
+	asrt.NotEmpty(r.Accounts())
+	acc := r.Accounts()[0]
+	sub, err := r.Client(acc.ID)
+	asrt.Empty(err)
+	orders, err := sub.GetOrdersV5(acc.ID, model.ALL, time.Time{}, time.Now(), 10)
+	asrt.Empty(err)
+	asrt.NotNil(orders)
+}
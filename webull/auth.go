@@ -108,10 +108,12 @@ func (c *Client) Token() (*oauth2.Token, error) {
 		return nil, err
 	}
 	tok.Expiry, err = time.Parse(DefaultTokenExpiryFormat, *response.TokenExpireTime)
+	c.tokenMu.Lock()
 	c.AccessTokenExpiration = tok.Expiry
 	tok.TokenType = "Token"
 	tok.AccessToken, c.AccessToken = *response.AccessToken, *response.AccessToken
 	tok.RefreshToken, c.RefreshToken = *response.RefreshToken, *response.RefreshToken
+	c.tokenMu.Unlock()
 	c.UUID = *response.Uuid
 	return &tok, nil
 }
@@ -202,6 +204,7 @@ func (c *Client) Login(creds Credentials) (err error) {
 	if err != nil {
 		return err
 	}
+	c.tokenMu.Lock()
 	c.AccessToken = *response.AccessToken
 	c.AccessTokenExpiration, err = time.Parse(DefaultTokenExpiryFormat, *response.TokenExpireTime)
 	if err != nil {
@@ -209,6 +212,7 @@ func (c *Client) Login(creds Credentials) (err error) {
 		c.AccessTokenExpiration = time.Now().AddDate(0,0,7)
 	}
 	c.RefreshToken = *response.RefreshToken
+	c.tokenMu.Unlock()
 	c.UUID = *response.Uuid
 
 	// update acct meta w/tokens
@@ -311,10 +315,12 @@ func (c *Client) TradeLogin(creds Credentials) (err error) {
 		return err
 	}
 	if *response.Success {
+		c.tokenMu.Lock()
 		c.TradeToken = *response.Data.TradeToken
 		tokenTimeMs := *response.Data.TradeTokenExpireIn
 		tmNowUtc := time.Now().UTC()
 		c.TradeTokenExpiration = tmNowUtc.Add(time.Duration(tokenTimeMs) * time.Millisecond) // Assuming ms?
+		c.tokenMu.Unlock()
 
 		// update acct meta w/tokens
 		c.updateMetaData()
@@ -413,10 +419,12 @@ func (c *Client) TradeLoginV5(creds Credentials) (err error) {
 	if err != nil {
 		return err
 	}
+	c.tokenMu.Lock()
 	c.TradeToken = *response.TradeToken
 	tokenTimeMs := *response.TradeTokenExpireIn
 	tmNowUtc := time.Now().UTC()
 	c.TradeTokenExpiration = tmNowUtc.Add(time.Duration(tokenTimeMs) * time.Millisecond) // Assuming ms?
+	c.tokenMu.Unlock()
 
 	// snap tokens
 	c.updateMetaData()
@@ -452,6 +460,11 @@ func (c *Client) GetMFA(creds Credentials) (err error) {
 		return errors.Wrap(err, "could not create request")
 	}
 	// Send and parse request
+	if c.DryRun != nil {
+		c.DryRun.record("GetMFA", *u, &queryParams, nil, nil)
+		return nil
+	}
+
 	err = c.PostAndDecode(*u, response, &headersMap, &queryParams, nil)
 	if err != nil {
 		return err
@@ -486,6 +499,8 @@ func (c *Client) updateMetaData() {
 }
 
 func (c *Client) haveMetaData(withTrade bool) bool {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
 
 	if c.MdProvider != nil {
 		var ok bool
@@ -550,6 +565,8 @@ func (c *Client) haveMetaData(withTrade bool) bool {
 }
 
 func (c *Client) expireTokens() {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
 	c.AccessTokenExpiration = time.Now().AddDate(-1, 0, 0)
 	c.AccessToken = ""
 	c.TradeTokenExpiration = time.Now().AddDate(-1, 0, 0)
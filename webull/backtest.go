@@ -0,0 +1,298 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	model "quantfu.com/webull/openapi"
+)
+
+// DefaultPeriodsPerYear annualises returns of daily bars.
+const DefaultPeriodsPerYear = 252
+
+// Broker is the live order surface strategies trade through. Client implements
+// it against Webull and PaperSimulator offline, so a strategy written against
+// Broker runs unmodified in a backtest.
+type Broker interface {
+	PlaceOrderV5(accountID int64, input model.PostStockOrderRequest) (*model.PostOrderResponse, error)
+	CancelOrderV5(accountID int64, orderId int64) (bool, error)
+	GetOrdersV5(accountID int64, status model.OrderStatus, stTime time.Time, endTime time.Time, count int32) ([]*model.OrderItemV5, error)
+	GetRealtimeStockQuote(tickerID int64) (*model.GetStockQuoteResponse, error)
+}
+
+var (
+	_ Broker = (*Client)(nil)
+	_ Broker = (*PaperSimulator)(nil)
+)
+
+// Bar is a price bar for a single interval.
+type Bar struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// Strategy receives every replayed bar and trades through `broker`.
+type Strategy interface {
+	OnBar(ctx context.Context, broker Broker, accountID int64, tickerID int64, bar Bar) error
+}
+
+// QuoteStrategy is implemented by strategies that also trade recorded streams,
+// see Backtest.RunStream.
+type QuoteStrategy interface {
+	OnQuote(ctx context.Context, broker Broker, accountID int64, tickerID int64, quote SimQuote) error
+}
+
+// StrategyFunc adapts a function to a Strategy.
+type StrategyFunc func(ctx context.Context, broker Broker, accountID int64, tickerID int64, bar Bar) error
+
+// OnBar implements Strategy
+func (f StrategyFunc) OnBar(ctx context.Context, broker Broker, accountID int64, tickerID int64, bar Bar) error {
+	return f(ctx, broker, accountID, tickerID, bar)
+}
+
+// StreamMessage is a recorded websocket message, as passed to websocket callbacks.
+type StreamMessage struct {
+	Topic   Topic
+	Message interface{}
+}
+
+// EquityPoint is the net liquidation value of the account at a point in time.
+type EquityPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// BacktestReport summarises a backtest.
+type BacktestReport struct {
+	Equity      []EquityPoint
+	Trades      []SimFill
+	StartValue  float64
+	EndValue    float64
+	TotalReturn float64
+	// MaxDrawdown is the largest peak to trough decline, as a fraction of the peak.
+	MaxDrawdown float64
+	// Sharpe is the annualised Sharpe ratio of the per-point returns. Stream
+	// replays measure it on the last point of each day.
+	Sharpe float64
+}
+
+// Backtest replays history through a Strategy against a PaperSimulator.
+type Backtest struct {
+	Strategy  Strategy
+	Sim       *PaperSimulator
+	AccountID int64
+	// PeriodsPerYear is the number of equity points per year, used to annualise
+	// the Sharpe ratio. Defaults to DefaultPeriodsPerYear, which matches daily
+	// bars and the daily points RunStream measures the Sharpe ratio on.
+	PeriodsPerYear float64
+	// RiskFreeRate is the annual rate subtracted from returns for the Sharpe ratio.
+	RiskFreeRate float64
+}
+
+// NewBacktest is a constructor for a Backtest of `strategy` on an account
+// starting with `cash`. Set commissions and slippage on Backtest.Sim.
+func NewBacktest(strategy Strategy, cash float64) *Backtest {
+	return &Backtest{
+		Strategy:       strategy,
+		Sim:            NewPaperSimulator(cash),
+		AccountID:      DefaultSimAccountID,
+		PeriodsPerYear: DefaultPeriodsPerYear,
+	}
+}
+
+type tickerBar struct {
+	tickerID int64
+	bar      Bar
+}
+
+// Run replays `bars`, keyed by ticker ID, in time order. Each bar first moves
+// the market to its open, then to its close with its high and low, before the
+// strategy sees it. Orders placed in OnBar are matched against the bar's close
+// straight away and against the following bars after that.
+func (b *Backtest) Run(ctx context.Context, bars map[int64][]Bar) (*BacktestReport, error) {
+	events := make([]tickerBar, 0)
+	for tickerID, list := range bars {
+		for _, bar := range list {
+			events = append(events, tickerBar{tickerID: tickerID, bar: bar})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].bar.Time.Equal(events[j].bar.Time) {
+			return events[i].bar.Time.Before(events[j].bar.Time)
+		}
+		return events[i].tickerID < events[j].tickerID
+	})
+
+	equity := []EquityPoint{}
+	for start := 0; start < len(events); {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := start
+		for end < len(events) && events[end].bar.Time.Equal(events[start].bar.Time) {
+			end++
+		}
+		group := events[start:end]
+		for _, e := range group {
+			b.Sim.UpdateQuote(e.tickerID, SimQuote{Time: e.bar.Time, Last: e.bar.Open})
+			b.Sim.UpdateQuote(e.tickerID, SimQuote{Time: e.bar.Time, Last: e.bar.Close, High: e.bar.High, Low: e.bar.Low})
+		}
+		for _, e := range group {
+			if err := b.Strategy.OnBar(ctx, b.Sim, b.AccountID, e.tickerID, e.bar); err != nil {
+				return nil, err
+			}
+		}
+		equity = append(equity, EquityPoint{Time: group[0].bar.Time, Value: b.Sim.NetLiquidation(b.AccountID)})
+		start = end
+	}
+	return b.report(equity), nil
+}
+
+// RunStream replays recorded quote messages. Strategies implementing
+// QuoteStrategy see the quote after every message. The report's equity has a
+// point per message, but its Sharpe ratio is measured on daily closes so
+// PeriodsPerYear keeps its daily meaning.
+func (b *Backtest) RunStream(ctx context.Context, messages []StreamMessage) (*BacktestReport, error) {
+	qs, _ := b.Strategy.(QuoteStrategy)
+	equity := []EquityPoint{}
+	for _, m := range messages {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := b.Sim.HandleQuote(m.Message); err != nil {
+			return nil, err
+		}
+		tickerID := int64(m.Topic.TickerID)
+		if tickerID == 0 {
+			id, err := messageTickerID(m.Message)
+			if err != nil {
+				return nil, err
+			}
+			tickerID = id
+		}
+		q, ok := b.Sim.Quote(tickerID)
+		if !ok {
+			continue
+		}
+		if qs != nil {
+			if err := qs.OnQuote(ctx, b.Sim, b.AccountID, tickerID, q); err != nil {
+				return nil, err
+			}
+		}
+		equity = append(equity, EquityPoint{Time: b.Sim.clock(), Value: b.Sim.NetLiquidation(b.AccountID)})
+	}
+	r := b.report(equity)
+	r.Sharpe = newBacktestReport(dailyCloses(equity), b.PeriodsPerYear, b.RiskFreeRate).Sharpe
+	return r, nil
+}
+
+// messageTickerID reads the ticker of a quote message recorded without its
+// topic, zero for messages that carry none.
+func messageTickerID(message interface{}) (int64, error) {
+	switch m := message.(type) {
+	case map[string]interface{}:
+		switch id := m["tickerId"].(type) {
+		case nil:
+			return 0, nil
+		case float64:
+			return int64(id), nil
+		case string:
+			n, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid tickerId %q", id)
+			}
+			return n, nil
+		default:
+			return 0, fmt.Errorf("invalid tickerId %v", id)
+		}
+	case Type102Message:
+		return int64(m.TickerID), nil
+	case *Type102Message:
+		return int64(m.TickerID), nil
+	case Type103Message:
+		return int64(m.TickerID), nil
+	case *Type103Message:
+		return int64(m.TickerID), nil
+	case Type104Message:
+		return int64(m.TickerID), nil
+	case *Type104Message:
+		return int64(m.TickerID), nil
+	}
+	return 0, nil
+}
+
+func (b *Backtest) report(equity []EquityPoint) *BacktestReport {
+	r := newBacktestReport(equity, b.PeriodsPerYear, b.RiskFreeRate)
+	r.Trades = b.Sim.Fills(b.AccountID)
+	return r
+}
+
+// dailyCloses keeps the last point of each calendar day of `equity`, which is
+// in time order.
+func dailyCloses(equity []EquityPoint) []EquityPoint {
+	closes := make([]EquityPoint, 0)
+	for _, p := range equity {
+		n := len(closes)
+		if n > 0 && closes[n-1].Time.Format("2006-01-02") == p.Time.Format("2006-01-02") {
+			closes[n-1] = p
+			continue
+		}
+		closes = append(closes, p)
+	}
+	return closes
+}
+
+// newBacktestReport computes returns, drawdown and Sharpe ratio of `equity`.
+func newBacktestReport(equity []EquityPoint, periodsPerYear, riskFreeRate float64) *BacktestReport {
+	r := &BacktestReport{Equity: equity}
+	if len(equity) == 0 {
+		return r
+	}
+	if periodsPerYear <= 0 {
+		periodsPerYear = DefaultPeriodsPerYear
+	}
+	r.StartValue = equity[0].Value
+	r.EndValue = equity[len(equity)-1].Value
+	if r.StartValue != 0 {
+		r.TotalReturn = r.EndValue/r.StartValue - 1
+	}
+
+	peak := equity[0].Value
+	returns := make([]float64, 0, len(equity))
+	for i, p := range equity {
+		if p.Value > peak {
+			peak = p.Value
+		}
+		if peak > 0 {
+			r.MaxDrawdown = math.Max(r.MaxDrawdown, (peak-p.Value)/peak)
+		}
+		if i > 0 && equity[i-1].Value != 0 {
+			returns = append(returns, p.Value/equity[i-1].Value-1-riskFreeRate/periodsPerYear)
+		}
+	}
+
+	if len(returns) < 2 {
+		return r
+	}
+	var mean, variance float64
+	for _, ret := range returns {
+		mean += ret
+	}
+	mean /= float64(len(returns))
+	for _, ret := range returns {
+		variance += (ret - mean) * (ret - mean)
+	}
+	variance /= float64(len(returns) - 1)
+	if variance > 0 {
+		r.Sharpe = mean / math.Sqrt(variance) * math.Sqrt(periodsPerYear)
+	}
+	return r
+}
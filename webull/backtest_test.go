@@ -0,0 +1,123 @@
+package webull
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+func testBars(closes ...float64) []Bar {
+	start := time.Date(2023, 1, 2, 21, 0, 0, 0, time.UTC)
+	bars := make([]Bar, len(closes))
+	for i, c := range closes {
+		bars[i] = Bar{Time: start.AddDate(0, 0, i), Open: c, High: c, Low: c, Close: c, Volume: 1000}
+	}
+	return bars
+}
+
+func TestBacktestBuyAndHold(t *testing.T) {
+	asrt := assert.New(t)
+	bought := false
+	strategy := StrategyFunc(func(ctx context.Context, broker Broker, accountID int64, tickerID int64, bar Bar) error {
+		if bought {
+			return nil
+		}
+		bought = true
+		_, err := broker.PlaceOrderV5(accountID, newTestMarketOrder(model.BUY, 10, tickerID))
+		return err
+	})
+
+	bt := NewBacktest(strategy, 1000)
+	report, err := bt.Run(context.Background(), map[int64][]Bar{1: testBars(50, 55, 44, 60)})
+	asrt.Empty(err)
+	asrt.Len(report.Equity, 4)
+	asrt.Len(report.Trades, 1)
+	asrt.Equal(50.0, report.Trades[0].Price)
+	asrt.Equal(1000.0, report.StartValue)
+	asrt.Equal(1100.0, report.EndValue)
+	asrt.InDelta(0.1, report.TotalReturn, 1e-9)
+	asrt.InDelta(110.0/1050, report.MaxDrawdown, 1e-9)
+	asrt.True(report.Sharpe > 0)
+
+	orders, err := bt.Sim.GetOrdersV5(bt.AccountID, model.FILLED, time.Time{}, time.Time{}, 10)
+	asrt.Empty(err)
+	asrt.Len(orders, 1)
+}
+
+func TestBacktestReport(t *testing.T) {
+	asrt := assert.New(t)
+	day := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	report := newBacktestReport([]EquityPoint{
+		{Time: day, Value: 100},
+		{Time: day.AddDate(0, 0, 1), Value: 110},
+		{Time: day.AddDate(0, 0, 2), Value: 99},
+		{Time: day.AddDate(0, 0, 3), Value: 121},
+	}, 252, 0)
+	asrt.InDelta(0.21, report.TotalReturn, 1e-9)
+	asrt.InDelta(0.1, report.MaxDrawdown, 1e-9)
+
+	returns := []float64{0.1, -0.1, 22.0 / 99}
+	mean := (returns[0] + returns[1] + returns[2]) / 3
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= 2
+	asrt.InDelta(mean/math.Sqrt(variance)*math.Sqrt(252), report.Sharpe, 1e-9)
+
+	asrt.Empty(newBacktestReport(nil, 252, 0).Equity)
+}
+
+type testQuoteStrategy struct {
+	quotes int
+}
+
+func (s *testQuoteStrategy) OnBar(ctx context.Context, broker Broker, accountID int64, tickerID int64, bar Bar) error {
+	return nil
+}
+
+func (s *testQuoteStrategy) OnQuote(ctx context.Context, broker Broker, accountID int64, tickerID int64, quote SimQuote) error {
+	s.quotes++
+	if s.quotes == 1 {
+		_, err := broker.PlaceOrderV5(accountID, newTestOrder(model.BUY, 1, 100, tickerID))
+		return err
+	}
+	return nil
+}
+
+func TestBacktestRunStream(t *testing.T) {
+	asrt := assert.New(t)
+	strategy := &testQuoteStrategy{}
+	bt := NewBacktest(strategy, 1000)
+	report, err := bt.RunStream(context.Background(), []StreamMessage{
+		{Topic: Topic{Type: 102, TickerID: 1}, Message: Type102Message{TickerID: 1, Close: "101", TradeStamp: 1672664400000}},
+		{Topic: Topic{Type: 102, TickerID: 1}, Message: Type102Message{TickerID: 1, Close: "99.5", TradeStamp: 1672664460000}},
+	})
+	asrt.Empty(err)
+	asrt.Equal(2, strategy.quotes)
+	asrt.Len(report.Trades, 1)
+	asrt.Equal(99.5, report.Trades[0].Price)
+	asrt.Len(report.Equity, 2)
+	asrt.Equal(0.0, report.Sharpe, "two ticks on one day give a single daily point")
+}
+
+func TestDailyCloses(t *testing.T) {
+	asrt := assert.New(t)
+	day := time.Date(2023, 1, 3, 15, 0, 0, 0, time.UTC)
+	closes := dailyCloses([]EquityPoint{
+		{Time: day, Value: 100},
+		{Time: day.Add(time.Minute), Value: 101},
+		{Time: day.AddDate(0, 0, 1), Value: 99},
+		{Time: day.AddDate(0, 0, 1).Add(time.Hour), Value: 102},
+		{Time: day.AddDate(0, 0, 2), Value: 103},
+	})
+	asrt.Equal([]EquityPoint{
+		{Time: day.Add(time.Minute), Value: 101},
+		{Time: day.AddDate(0, 0, 1).Add(time.Hour), Value: 102},
+		{Time: day.AddDate(0, 0, 2), Value: 103},
+	}, closes)
+}
@@ -0,0 +1,158 @@
+package webull
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// barsPageSize is the most bars Webull returns per chart request.
+const barsPageSize = 800
+
+// BarInterval is the width of a price bar.
+type BarInterval string
+
+// Bar intervals accepted by GetBars
+const (
+	BarMinute   BarInterval = "m1"
+	Bar5Minute  BarInterval = "m5"
+	Bar15Minute BarInterval = "m15"
+	Bar30Minute BarInterval = "m30"
+	BarHour     BarInterval = "h1"
+	Bar2Hour    BarInterval = "h2"
+	Bar4Hour    BarInterval = "h4"
+	BarDay      BarInterval = "d1"
+	BarWeek     BarInterval = "w1"
+)
+
+// chartResponse is a page of bars, newest first, each encoded as
+// "time,open,close,high,low,preClose,volume,vwap".
+type chartResponse struct {
+	TickerID ID       `json:"tickerId"`
+	HasMore  *bool    `json:"hasMore"`
+	Data     []string `json:"data"`
+}
+
+// GetBars gets `interval` bars of ticker `tickerID` between `from` and `to`,
+// oldest first. A zero `from` pages back to the start of the ticker's history
+// and a zero `to` means now. Long ranges are fetched in pages and merged.
+// `adjusted` adjusts prices for splits and dividends and `extendedHours`
+// includes pre and post market bars for intraday intervals.
+func (c *Client) GetBars(tickerID int64, interval BarInterval, from, to time.Time, adjusted, extendedHours bool) ([]Bar, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if !from.IsZero() && from.After(to) {
+		return nil, fmt.Errorf("bars range starts %s after it ends %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	var (
+		seen   = make(map[int64]bool)
+		bars   = make([]Bar, 0)
+		cursor = to
+	)
+	for {
+		page, hasMore, err := c.getBarsPage(tickerID, interval, cursor, barsPageSize, adjusted, extendedHours)
+		if err != nil {
+			return nil, err
+		}
+		oldest := cursor
+		for _, bar := range page {
+			if bar.Time.Before(oldest) {
+				oldest = bar.Time
+			}
+			if seen[bar.Time.Unix()] || bar.Time.After(to) || (!from.IsZero() && bar.Time.Before(from)) {
+				continue
+			}
+			seen[bar.Time.Unix()] = true
+			bars = append(bars, bar)
+		}
+		// stop once the range is covered or the cursor stops moving back
+		if len(page) == 0 || !hasMore || !oldest.Before(cursor) || (!from.IsZero() && !oldest.After(from)) {
+			break
+		}
+		cursor = oldest.Add(-time.Second)
+	}
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Time.Before(bars[j].Time) })
+	return bars, nil
+}
+
+// getBarsPage gets up to `count` bars ending at `end`.
+func (c *Client) getBarsPage(tickerID int64, interval BarInterval, end time.Time, count int, adjusted, extendedHours bool) ([]Bar, bool, error) {
+	var (
+		u, _        = url.Parse(BrokerQuotesGWEndpointV + "/quote/charts/query")
+		response    []chartResponse
+		headersMap  = make(map[string]string)
+		queryParams = make(map[string]string)
+	)
+
+	headersMap[HeaderKeyAccessToken] = c.AccessToken
+	headersMap[HeaderKeyDeviceID] = c.DeviceID
+
+	queryParams["tickerIds"] = strconv.FormatInt(tickerID, 10)
+	queryParams["type"] = string(interval)
+	queryParams["count"] = strconv.Itoa(count)
+	queryParams["timestamp"] = strconv.FormatInt(end.Unix(), 10)
+	queryParams["restorationType"] = "0"
+	if adjusted {
+		queryParams["restorationType"] = "1"
+	}
+	queryParams["extendTrading"] = "0"
+	if extendedHours {
+		queryParams["extendTrading"] = "1"
+	}
+
+	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(response) == 0 {
+		return nil, false, nil
+	}
+	bars := make([]Bar, 0, len(response[0].Data))
+	for _, row := range response[0].Data {
+		bar, err := parseBarRow(row)
+		if err != nil {
+			return nil, false, err
+		}
+		bars = append(bars, bar)
+	}
+	// without hasMore, a full page means there may be older bars
+	hasMore := len(bars) >= count
+	if response[0].HasMore != nil {
+		hasMore = *response[0].HasMore
+	}
+	return bars, hasMore, nil
+}
+
+// parseBarRow parses a chart row, treating "null" values as zero.
+func parseBarRow(row string) (Bar, error) {
+	cols := strings.Split(row, ",")
+	if len(cols) < 7 {
+		return Bar{}, fmt.Errorf("invalid bar %q", row)
+	}
+	values := make([]float64, len(cols))
+	for i, col := range cols {
+		col = strings.TrimSpace(col)
+		if col == "" || col == "null" {
+			continue
+		}
+		v, err := strconv.ParseFloat(col, 64)
+		if err != nil {
+			return Bar{}, errors.Wrapf(err, "invalid bar %q", row)
+		}
+		values[i] = v
+	}
+	return Bar{
+		Time:   time.Unix(int64(values[0]), 0).UTC(),
+		Open:   values[1],
+		Close:  values[2],
+		High:   values[3],
+		Low:    values[4],
+		Volume: values[6],
+	}, nil
+}
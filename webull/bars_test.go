@@ -0,0 +1,111 @@
+package webull
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+// chartServer serves daily bars from a fixed history, newest first, honouring
+// the count and timestamp query parameters.
+type chartServer struct {
+	history  []string
+	requests []*http.Request
+}
+
+func (t *chartServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	t.requests = append(t.requests, req)
+	count, _ := strconv.Atoi(req.URL.Query().Get("count"))
+	end, _ := strconv.ParseInt(req.URL.Query().Get("timestamp"), 10, 64)
+	page := make([]string, 0)
+	for i := len(t.history) - 1; i >= 0 && len(page) < count; i-- {
+		ts, _ := strconv.ParseInt(strings.SplitN(t.history[i], ",", 2)[0], 10, 64)
+		if ts <= end {
+			page = append(page, `"`+t.history[i]+`"`)
+		}
+	}
+	_, _ = io.WriteString(w, `[{"tickerId":913256135,"data":[`+strings.Join(page, ",")+`]}]`)
+}
+
+func TestParseBarRow(t *testing.T) {
+	asrt := assert.New(t)
+	bar, err := parseBarRow("1677862800,148.04,151.03,151.11,147.33,145.91,58642519,150.82")
+	asrt.Empty(err)
+	asrt.Equal(time.Unix(1677862800, 0).UTC(), bar.Time)
+	asrt.Equal(148.04, bar.Open)
+	asrt.Equal(151.03, bar.Close)
+	asrt.Equal(151.11, bar.High)
+	asrt.Equal(147.33, bar.Low)
+	asrt.Equal(58642519.0, bar.Volume)
+
+	bar, err = parseBarRow("1677862800,1,2,3,4,null,0")
+	asrt.Empty(err)
+	asrt.Equal(4.0, bar.Low)
+
+	_, err = parseBarRow("1677862800,1,2")
+	asrt.Error(err)
+	_, err = parseBarRow("1677862800,1,2,x,4,5,6")
+	asrt.Error(err)
+}
+
+func TestGetBarsChunking(t *testing.T) {
+	asrt := assert.New(t)
+	start := time.Date(2020, 1, 1, 21, 0, 0, 0, time.UTC)
+	server := &chartServer{}
+	for i := 0; i < 2000; i++ {
+		ts := start.AddDate(0, 0, i).Unix()
+		server.history = append(server.history, strconv.FormatInt(ts, 10)+",1,2,3,0.5,1,100,1.5")
+	}
+	c := newTestClient(t, server)
+
+	from, to := start.AddDate(0, 0, 100), start.AddDate(0, 0, 1899)
+	bars, err := c.GetBars(913256135, BarDay, from, to, true, false)
+	asrt.Empty(err)
+	asrt.Len(bars, 1800)
+	asrt.Equal(from, bars[0].Time)
+	asrt.Equal(to, bars[len(bars)-1].Time)
+	for i := 1; i < len(bars); i++ {
+		asrt.True(bars[i].Time.After(bars[i-1].Time))
+	}
+	asrt.Len(server.requests, 3)
+	query := server.requests[0].URL.Query()
+	asrt.Equal("d1", query.Get("type"))
+	asrt.Equal("1", query.Get("restorationType"))
+	asrt.Equal("0", query.Get("extendTrading"))
+
+	_, err = c.GetBars(913256135, BarDay, to, from, false, false)
+	asrt.Error(err)
+
+	// without a start the whole history is read
+	bars, err = c.GetBars(913256135, BarDay, time.Time{}, time.Time{}, true, false)
+	asrt.Empty(err)
+	asrt.Len(bars, 2000)
+	asrt.Equal(start, bars[0].Time)
+}
+
+func TestGetBars(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	tickerID, err := c.GetTickerID("AAPL")
+	asrt.Empty(err)
+	bars, err := c.GetBars(tickerID, BarDay, time.Now().AddDate(-5, 0, 0), time.Time{}, true, false)
+	asrt.Empty(err)
+	asrt.NotEmpty(bars)
+}
@@ -0,0 +1,349 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// calendarPageSize is the page size used when loading calendar events.
+const calendarPageSize = 50
+
+// CalendarEventType is a kind of corporate event.
+type CalendarEventType string
+
+// Corporate events
+const (
+	EarningsEvent CalendarEventType = "earnings"
+	DividendEvent CalendarEventType = "dividend"
+	SplitEvent    CalendarEventType = "split"
+	IPOEvent      CalendarEventType = "ipo"
+)
+
+// CalendarEvent is a scheduled or past corporate event. Only the fields of
+// its Type are set.
+type CalendarEvent struct {
+	Type     CalendarEventType
+	TickerID int64
+	Symbol   string
+	Name     string
+	// Date is the release date of earnings, the ex-date of dividends, the
+	// effective date of splits and the listing date of IPOs.
+	Date time.Time
+
+	FiscalYear    int
+	FiscalQuarter int
+	// Timing is when earnings are released relative to the session, e.g.
+	// "bmo" before the open or "amc" after the close.
+	Timing      string
+	EPSEstimate float64
+	EPS         float64
+
+	ExDate       time.Time
+	RecordDate   time.Time
+	PayDate      time.Time
+	AnnounceDate time.Time
+	Amount       float64
+
+	// SplitFrom and SplitTo are the ratio of a split, e.g. 1 and 4 for a 4 for
+	// 1 split.
+	SplitFrom float64
+	SplitTo   float64
+
+	PriceLow  float64
+	PriceHigh float64
+	Shares    float64
+}
+
+// CalendarQuery selects calendar events. Zero fields select everything.
+type CalendarQuery struct {
+	From      time.Time
+	To        time.Time
+	TickerIDs []int64
+	// Symbols are resolved to ticker IDs, in RegionID, before the request.
+	Symbols []string
+	// Resolver resolves Symbols. Defaults to an uncached SymbolResolver.
+	Resolver *SymbolResolver
+	// RegionID limits events to a region. Defaults to RegionUS.
+	RegionID int64
+}
+
+// GetCalendar gets every `eventType` event matching `q`, ordered by date.
+func (c *Client) GetCalendar(ctx context.Context, eventType CalendarEventType, q CalendarQuery) ([]CalendarEvent, error) {
+	all, err := c.GetCalendarPager(eventType, q).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]CalendarEvent, 0, len(all))
+	for _, e := range all {
+		if q.matches(e) {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+	return events, nil
+}
+
+// GetCalendarPager walks every page of `eventType` events for `q`. Paging
+// stops when a page repeats the previous one, in case the endpoint ignores the
+// page index.
+func (c *Client) GetCalendarPager(eventType CalendarEventType, q CalendarQuery) *Pager[CalendarEvent] {
+	var (
+		prev     []CalendarEvent
+		resolved bool
+	)
+	return NewPager(func(ctx context.Context, cursor string) ([]CalendarEvent, string, error) {
+		if !resolved {
+			ids, err := c.calendarTickerIDs(q)
+			if err != nil {
+				return nil, "", err
+			}
+			q.TickerIDs, resolved = ids, true
+		}
+		page := 1
+		if cursor != "" {
+			page, _ = strconv.Atoi(cursor)
+		}
+		events, err := c.getCalendarPage(eventType, q, page, calendarPageSize)
+		if err != nil {
+			return nil, "", err
+		}
+		if page > 1 && sameCalendarEvents(events, prev) {
+			return nil, "", nil
+		}
+		prev = events
+		if len(events) < calendarPageSize {
+			return events, "", nil
+		}
+		return events, strconv.Itoa(page + 1), nil
+	})
+}
+
+// calendarTickerIDs is q.TickerIDs with q.Symbols resolved and added.
+func (c *Client) calendarTickerIDs(q CalendarQuery) ([]int64, error) {
+	if len(q.Symbols) == 0 {
+		return q.TickerIDs, nil
+	}
+	r := q.Resolver
+	if r == nil {
+		var err error
+		if r, err = NewSymbolResolver(c, ""); err != nil {
+			return nil, err
+		}
+		if q.RegionID != 0 {
+			r.RegionID = q.RegionID
+		}
+	}
+	infos, err := r.ResolveAll(q.Symbols, "")
+	if err != nil {
+		return nil, err
+	}
+	ids := append([]int64(nil), q.TickerIDs...)
+	for _, symbol := range q.Symbols {
+		ids = append(ids, infos[symbol].ID)
+	}
+	return ids, nil
+}
+
+// sameCalendarEvents reports whether two pages hold the same events.
+func sameCalendarEvents(a, b []CalendarEvent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].TickerID != b[i].TickerID || a[i].Symbol != b[i].Symbol || !a[i].Date.Equal(b[i].Date) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetUpcomingEarnings gets the earnings of `tickerIDs` released within
+// `within` from now, for example to flatten positions ahead of them.
+func (c *Client) GetUpcomingEarnings(ctx context.Context, tickerIDs []int64, within time.Duration) ([]CalendarEvent, error) {
+	now := time.Now()
+	return c.GetCalendar(ctx, EarningsEvent, CalendarQuery{
+		From:      now.Truncate(24 * time.Hour),
+		To:        now.Add(within),
+		TickerIDs: tickerIDs,
+	})
+}
+
+func (c *Client) getCalendarPage(eventType CalendarEventType, q CalendarQuery, page, count int) ([]CalendarEvent, error) {
+	var (
+		u, _        = url.Parse(BrokerQuotesGWEndpointV + "/bgw/explore/calendar/" + string(eventType))
+		response    calendarResponse
+		headersMap  = make(map[string]string)
+		queryParams = make(map[string]string)
+	)
+
+	headersMap[HeaderKeyAccessToken] = c.AccessToken
+	headersMap[HeaderKeyDeviceID] = c.DeviceID
+
+	regionID := q.RegionID
+	if regionID == 0 {
+		regionID = RegionUS
+	}
+	queryParams["regionId"] = strconv.FormatInt(regionID, 10)
+	queryParams["pageIndex"] = strconv.Itoa(page)
+	queryParams["pageSize"] = strconv.Itoa(count)
+	if !q.From.IsZero() {
+		queryParams["startDate"] = q.From.Format("2006-01-02")
+	}
+	if !q.To.IsZero() {
+		queryParams["endDate"] = q.To.Format("2006-01-02")
+	}
+	if len(q.TickerIDs) > 0 {
+		ids := make([]string, len(q.TickerIDs))
+		for i, id := range q.TickerIDs {
+			ids[i] = strconv.FormatInt(id, 10)
+		}
+		queryParams["tickerIds"] = strings.Join(ids, ",")
+	}
+
+	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
+	if err != nil {
+		return nil, err
+	}
+	return newCalendarEvents(eventType, response)
+}
+
+func (q CalendarQuery) matches(e CalendarEvent) bool {
+	day := e.Date.Truncate(24 * time.Hour)
+	if !q.From.IsZero() && day.Before(q.From.Truncate(24*time.Hour)) {
+		return false
+	}
+	if !q.To.IsZero() && day.After(q.To) {
+		return false
+	}
+	if len(q.TickerIDs) == 0 && len(q.Symbols) == 0 {
+		return true
+	}
+	for _, id := range q.TickerIDs {
+		if id == e.TickerID {
+			return true
+		}
+	}
+	for _, s := range q.Symbols {
+		if strings.EqualFold(s, e.Symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// calendarResponse is the body of the calendar endpoints: each event's ticker
+// and its values, which depend on the event type.
+type calendarResponse struct {
+	Data []struct {
+		Ticker struct {
+			TickerID ID     `json:"tickerId"`
+			Symbol   string `json:"symbol"`
+			Name     string `json:"name"`
+		} `json:"ticker"`
+		Values struct {
+			ReleaseDate   *Timestamp `json:"releaseDate"`
+			FiscalYear    *Number    `json:"fiscalYear"`
+			FiscalPeriod  *Number    `json:"fiscalPeriod"`
+			ReleaseTime   string     `json:"releaseTime"`
+			EpsEstimate   *Number    `json:"epsEstimate"`
+			Eps           *Number    `json:"eps"`
+			ExDate        *Timestamp `json:"exDate"`
+			RecordDate    *Timestamp `json:"recordDate"`
+			PayDate       *Timestamp `json:"payDate"`
+			AnnounceDate  *Timestamp `json:"announceDate"`
+			Dividend      *Number    `json:"dividend"`
+			EffectiveDate *Timestamp `json:"effectiveDate"`
+			SplitRatio    string     `json:"splitRatio"`
+			ListDate      *Timestamp `json:"listDate"`
+			PriceLow      *Number    `json:"priceLow"`
+			PriceHigh     *Number    `json:"priceHigh"`
+			Shares        *Number    `json:"shares"`
+		} `json:"values"`
+	} `json:"data"`
+}
+
+// newCalendarEvents reads `eventType` events. Every event must carry its
+// ticker and the date Date is set from, and splits their ratio.
+func newCalendarEvents(eventType CalendarEventType, response calendarResponse) ([]CalendarEvent, error) {
+	events := make([]CalendarEvent, 0, len(response.Data))
+	for _, d := range response.Data {
+		v := d.Values
+		e := CalendarEvent{
+			Type:          eventType,
+			TickerID:      d.Ticker.TickerID.Int64(),
+			Symbol:        d.Ticker.Symbol,
+			Name:          d.Ticker.Name,
+			FiscalYear:    int(optionalNumber(v.FiscalYear)),
+			FiscalQuarter: int(optionalNumber(v.FiscalPeriod)),
+			Timing:        strings.ToLower(v.ReleaseTime),
+			EPSEstimate:   optionalNumber(v.EpsEstimate),
+			EPS:           optionalNumber(v.Eps),
+			ExDate:        optionalTime(v.ExDate),
+			RecordDate:    optionalTime(v.RecordDate),
+			PayDate:       optionalTime(v.PayDate),
+			AnnounceDate:  optionalTime(v.AnnounceDate),
+			Amount:        optionalNumber(v.Dividend),
+			PriceLow:      optionalNumber(v.PriceLow),
+			PriceHigh:     optionalNumber(v.PriceHigh),
+			Shares:        optionalNumber(v.Shares),
+		}
+		if e.TickerID == 0 {
+			return nil, fmt.Errorf("%s event is missing tickerId", eventType)
+		}
+		var date *Timestamp
+		switch eventType {
+		case EarningsEvent:
+			date = v.ReleaseDate
+		case DividendEvent:
+			date = v.ExDate
+		case SplitEvent:
+			date = v.EffectiveDate
+			if err := e.setSplitRatio(v.SplitRatio); err != nil {
+				return nil, err
+			}
+		case IPOEvent:
+			date = v.ListDate
+		default:
+			return nil, fmt.Errorf("unknown calendar event type %q", eventType)
+		}
+		if date == nil || date.IsZero() {
+			return nil, fmt.Errorf("%s event of %s is missing its date", eventType, e.Symbol)
+		}
+		e.Date = date.Time
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// setSplitRatio reads a "4:1" split ratio, new shares to old.
+func (e *CalendarEvent) setSplitRatio(ratio string) error {
+	parts := strings.SplitN(ratio, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("split of %s has invalid splitRatio %q", e.Symbol, ratio)
+	}
+	to, errTo := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	from, errFrom := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if errTo != nil || errFrom != nil || to <= 0 || from <= 0 {
+		return fmt.Errorf("split of %s has invalid splitRatio %q", e.Symbol, ratio)
+	}
+	e.SplitTo, e.SplitFrom = to, from
+	return nil
+}
+
+// SplitRatio is the number of new shares per old share, or 0 when unknown.
+func (e CalendarEvent) SplitRatio() float64 {
+	if e.SplitFrom == 0 {
+		return 0
+	}
+	return e.SplitTo / e.SplitFrom
+}
+
+// String describes the event, e.g. "AAPL earnings 2023-05-04".
+func (e CalendarEvent) String() string {
+	return fmt.Sprintf("%s %s %s", e.Symbol, e.Type, e.Date.Format("2006-01-02"))
+}
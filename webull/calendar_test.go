@@ -0,0 +1,164 @@
+package webull
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+func decodeCalendar(t *testing.T, eventType CalendarEventType, body string) ([]CalendarEvent, error) {
+	var response calendarResponse
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatal(err)
+	}
+	return newCalendarEvents(eventType, response)
+}
+
+// The calendar bodies are hand-written, not captured.
+func TestCalendarEvents(t *testing.T) {
+	asrt := assert.New(t)
+	events, err := decodeCalendar(t, EarningsEvent, `{"data":[{"ticker":{"tickerId":913256135,"symbol":"AAPL","name":"Apple Inc"},
+		"values":{"releaseDate":"2023-05-04","fiscalYear":2023,"fiscalPeriod":2,"releaseTime":"AMC","epsEstimate":"1.43","eps":"1.52"}}]}`)
+	asrt.Empty(err)
+	asrt.Len(events, 1)
+	earnings := events[0]
+	asrt.Equal(EarningsEvent, earnings.Type)
+	asrt.Equal(int64(913256135), earnings.TickerID)
+	asrt.Equal("Apple Inc", earnings.Name)
+	asrt.Equal(time.Date(2023, 5, 4, 0, 0, 0, 0, time.UTC), earnings.Date)
+	asrt.Equal(2, earnings.FiscalQuarter)
+	asrt.Equal("amc", earnings.Timing)
+	asrt.Equal(1.43, earnings.EPSEstimate)
+	asrt.Equal(1.52, earnings.EPS)
+
+	events, err = decodeCalendar(t, DividendEvent, `{"data":[{"ticker":{"tickerId":913256135,"symbol":"AAPL"},
+		"values":{"exDate":"2023-05-12","recordDate":"2023-05-15","payDate":"2023-05-18","announceDate":"2023-05-04","dividend":"0.24"}}]}`)
+	asrt.Empty(err)
+	dividend := events[0]
+	asrt.Equal(time.Date(2023, 5, 12, 0, 0, 0, 0, time.UTC), dividend.Date)
+	asrt.Equal(time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC), dividend.RecordDate)
+	asrt.Equal(time.Date(2023, 5, 18, 0, 0, 0, 0, time.UTC), dividend.PayDate)
+	asrt.Equal(0.24, dividend.Amount)
+
+	events, err = decodeCalendar(t, SplitEvent, `{"data":[{"ticker":{"tickerId":913255598,"symbol":"NVDA"},
+		"values":{"effectiveDate":"2021-07-20","splitRatio":"4:1"}}]}`)
+	asrt.Empty(err)
+	split := events[0]
+	asrt.Equal(4.0, split.SplitRatio())
+	asrt.Equal(time.Date(2021, 7, 20, 0, 0, 0, 0, time.UTC), split.Date)
+
+	events, err = decodeCalendar(t, IPOEvent, `{"data":[{"ticker":{"tickerId":950188536,"symbol":"ARM"},
+		"values":{"listDate":"2023-09-14","priceLow":"47","priceHigh":"51","shares":"95500000"}}]}`)
+	asrt.Empty(err)
+	ipo := events[0]
+	asrt.Equal(51.0, ipo.PriceHigh)
+	asrt.Equal(9.55e7, ipo.Shares)
+	asrt.Equal(time.Date(2023, 9, 14, 0, 0, 0, 0, time.UTC), ipo.Date)
+
+	// an event without the date of its type is an error, not a zero date
+	for eventType, body := range map[CalendarEventType]string{
+		EarningsEvent: `{"data":[{"ticker":{"tickerId":1},"values":{"date":"2023-05-04"}}]}`,
+		DividendEvent: `{"data":[{"ticker":{"tickerId":1},"values":{"payDate":"2023-05-18"}}]}`,
+		SplitEvent:    `{"data":[{"ticker":{"tickerId":1},"values":{"effectiveDate":"2021-07-20","splitRatio":"4 for 1"}}]}`,
+		IPOEvent:      `{"data":[{"ticker":{"symbol":"ARM"},"values":{"listDate":"2023-09-14"}}]}`,
+	} {
+		_, err := decodeCalendar(t, eventType, body)
+		asrt.Error(err, body)
+	}
+
+	q := CalendarQuery{From: time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC), To: time.Date(2023, 5, 31, 0, 0, 0, 0, time.UTC)}
+	asrt.True(q.matches(earnings))
+	asrt.False(q.matches(split))
+	q.Symbols = []string{"nvda"}
+	asrt.False(q.matches(earnings))
+	q = CalendarQuery{TickerIDs: []int64{913256135}}
+	asrt.True(q.matches(dividend))
+	asrt.False(q.matches(ipo))
+}
+
+// calendarServer serves `total` earnings events across pages, or always the
+// first page when `ignorePage` is set.
+type calendarServer struct {
+	total      int
+	ignorePage bool
+	requests   []*http.Request
+}
+
+func (t *calendarServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	t.requests = append(t.requests, req)
+	page, _ := strconv.Atoi(req.URL.Query().Get("pageIndex"))
+	if t.ignorePage {
+		page = 1
+	}
+	size, _ := strconv.Atoi(req.URL.Query().Get("pageSize"))
+	items := make([]string, 0)
+	start := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i := (page - 1) * size; i < page*size && i < t.total; i++ {
+		// served newest first
+		day := start.AddDate(0, 0, (t.total-i)%28).Format("2006-01-02")
+		items = append(items, `{"ticker":{"tickerId":1,"symbol":"A"},"values":{"releaseDate":"`+day+`"}}`)
+	}
+	_, _ = io.WriteString(w, `{"data":[`+strings.Join(items, ",")+`]}`)
+}
+
+func TestGetCalendarPages(t *testing.T) {
+	asrt := assert.New(t)
+	server := &calendarServer{total: calendarPageSize + 3}
+	c := newTestClient(t, server)
+	from := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	events, err := c.GetCalendar(context.Background(), EarningsEvent, CalendarQuery{From: from, TickerIDs: []int64{1, 2}})
+	asrt.Empty(err)
+	asrt.Len(events, calendarPageSize+3)
+	asrt.Len(server.requests, 2)
+	for i := 1; i < len(events); i++ {
+		asrt.False(events[i].Date.Before(events[i-1].Date))
+	}
+	asrt.Equal(EarningsEvent, events[0].Type)
+	query := server.requests[0].URL.Query()
+	asrt.Equal("2023-05-01", query.Get("startDate"))
+	asrt.Equal("1,2", query.Get("tickerIds"))
+	asrt.Equal("6", query.Get("regionId"))
+
+	server = &calendarServer{total: 3 * calendarPageSize, ignorePage: true}
+	c = newTestClient(t, server)
+	events, err = c.GetCalendar(context.Background(), EarningsEvent, CalendarQuery{})
+	asrt.Empty(err)
+	asrt.Len(events, calendarPageSize)
+	asrt.Len(server.requests, 2, "stops when a page repeats")
+
+	r, _ := newTestSymbolResolver("")
+	server = &calendarServer{total: 1}
+	c = newTestClient(t, server)
+	_, err = c.GetCalendar(context.Background(), EarningsEvent, CalendarQuery{TickerIDs: []int64{1}, Symbols: []string{"SHOP"}, Resolver: r})
+	asrt.Empty(err)
+	asrt.Equal("1,950051491", server.requests[0].URL.Query().Get("tickerIds"))
+	_, err = c.GetCalendar(context.Background(), EarningsEvent, CalendarQuery{Symbols: []string{"XYZ"}, Resolver: r})
+	asrt.Error(err)
+}
+
+func TestGetUpcomingEarnings(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	events, err := c.GetUpcomingEarnings(context.Background(), []int64{913256135}, 120*24*time.Hour)
+	asrt.Empty(err)
+	asrt.NotNil(events)
+}
@@ -0,0 +1,246 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	model "quantfu.com/webull/openapi"
+)
+
+const (
+	// DefaultCancelParallelism bounds how many cancel requests CancelAll sends at once.
+	DefaultCancelParallelism = 4
+	// cancelPageSize is the page size used when listing working orders to cancel.
+	cancelPageSize = 100
+	// cancelVerifyAttempts is how many times CancelAll re-reads orders still
+	// pending cancellation before giving up on them.
+	cancelVerifyAttempts = 5
+)
+
+// cancelVerifyDelay is the wait between re-reads of orders pending cancellation.
+var cancelVerifyDelay = time.Second
+
+// TradingAccount identifies a live (V5) or paper account.
+type TradingAccount struct {
+	ID    int64
+	Paper bool
+}
+
+// CancelFilter selects which working orders CancelAll cancels. The zero value
+// selects every working order.
+type CancelFilter struct {
+	TickerIDs []int64
+	Action    model.OrderSide
+	// Match is an optional predicate applied after the other fields.
+	Match func(*model.OrderItemV5) bool
+}
+
+// CancelResult is the outcome of cancelling a single order.
+type CancelResult struct {
+	OrderID string
+	Order   *model.OrderItemV5
+	// Cancelled is true once the order was confirmed cancelled. An order that
+	// filled or was rejected instead is not cancelled and carries an Err.
+	Cancelled bool
+	// Status is the order status observed when re-verifying, if known.
+	Status string
+	Err    error
+}
+
+// CancelAll cancels every working order on `account` matching `filter`. It
+// pages through all working orders, cancels them with bounded parallelism, then
+// re-reads the orders to confirm each cancellation. The returned error is
+// non-nil if any order could not be confirmed cancelled, including orders that
+// filled or were rejected before the cancel took effect.
+func (c *Client) CancelAll(ctx context.Context, account TradingAccount, filter CancelFilter) ([]CancelResult, error) {
+	working, err := c.workingOrders(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CancelResult, 0, len(working))
+	for _, o := range working {
+		if filter.matches(o) {
+			results = append(results, CancelResult{OrderID: o.GetOrderId(), Order: o})
+		}
+	}
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, DefaultCancelParallelism)
+	)
+	for i := range results {
+		wg.Add(1)
+		go func(r *CancelResult) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				r.Err = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+			r.Err = c.cancelOne(account, r.OrderID)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	if err = c.verifyCancelled(ctx, account, results); err != nil {
+		return results, err
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Cancelled {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d orders not confirmed cancelled", failed, len(results))
+	}
+	return results, nil
+}
+
+// workingOrders lists every working order on `account`.
+func (c *Client) workingOrders(ctx context.Context, account TradingAccount) ([]*model.OrderItemV5, error) {
+	if account.Paper {
+		return c.GetPaperOrdersPager(account.ID, model.WORKING, time.Time{}, cancelPageSize).All(ctx)
+	}
+	return c.GetOrdersV5Pager(account.ID, model.WORKING, time.Time{}, cancelPageSize).All(ctx)
+}
+
+func (c *Client) cancelOne(account TradingAccount, orderID string) error {
+	if account.Paper {
+		_, err := c.CancelPaperOrder(account.ID, orderID)
+		return err
+	}
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid order id %q", orderID)
+	}
+	ok, err := c.CancelOrderV5(account.ID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("cancel of order %s not accepted", orderID)
+	}
+	return nil
+}
+
+// verifyCancelled re-reads the orders of `results` until each is cancelled or
+// ended some other way, retrying briefly while cancellations are pending.
+func (c *Client) verifyCancelled(ctx context.Context, account TradingAccount, results []CancelResult) error {
+	pending := make(map[string]*CancelResult, len(results))
+	for i := range results {
+		pending[results[i].OrderID] = &results[i]
+	}
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			if attempt == cancelVerifyAttempts {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(cancelVerifyDelay):
+			}
+		}
+		statuses, err := c.orderStatuses(ctx, account, pending)
+		if err != nil {
+			return err
+		}
+		for id, r := range pending {
+			r.Status = statuses[id]
+			switch {
+			case isCancelledStatus(r.Status):
+				r.Cancelled = true
+				// the order is gone either way, a request error no longer matters
+				r.Err = nil
+			case isTerminalStatus(r.Status):
+				r.Err = fmt.Errorf("order %s ended %s instead of cancelled", id, r.Status)
+			default:
+				continue
+			}
+			delete(pending, id)
+		}
+	}
+	for id, r := range pending {
+		if r.Err == nil {
+			status := r.Status
+			if status == "" {
+				status = "not found"
+			}
+			r.Err = fmt.Errorf("order %s still %s after cancel", id, status)
+		}
+	}
+	return nil
+}
+
+// orderStatuses pages through the orders on `account`, newest first, until the
+// status of every order in `wanted` is known.
+func (c *Client) orderStatuses(ctx context.Context, account TradingAccount, wanted map[string]*CancelResult) (map[string]string, error) {
+	var pager *Pager[*model.OrderItemV5]
+	if account.Paper {
+		pager = c.GetPaperOrdersPager(account.ID, model.ALL, time.Time{}, cancelPageSize)
+	} else {
+		pager = c.GetOrdersV5Pager(account.ID, model.ALL, time.Time{}, cancelPageSize)
+	}
+	statuses := make(map[string]string, len(wanted))
+	for len(statuses) < len(wanted) {
+		o, err := pager.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := wanted[o.GetOrderId()]; ok {
+			statuses[o.GetOrderId()] = o.GetStatus()
+		}
+	}
+	return statuses, nil
+}
+
+// isCancelledStatus reports whether an order status means it was cancelled.
+func isCancelledStatus(status string) bool {
+	switch strings.ToLower(status) {
+	case "cancelled", "canceled":
+		return true
+	}
+	return false
+}
+
+func (f CancelFilter) matches(o *model.OrderItemV5) bool {
+	if o == nil || o.OrderId == nil {
+		return false
+	}
+	if f.Action != "" && !strings.EqualFold(o.GetAction(), string(f.Action)) {
+		return false
+	}
+	if len(f.TickerIDs) > 0 {
+		found := false
+		for _, item := range o.Items {
+			for _, id := range f.TickerIDs {
+				if item.GetTickerId() == id {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Match != nil && !f.Match(o) {
+		return false
+	}
+	return true
+}
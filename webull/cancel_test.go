@@ -0,0 +1,136 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+func TestCancelFilterMatches(t *testing.T) {
+	asrt := assert.New(t)
+	o := &model.OrderItemV5{OrderId: model.PtrString("1")}
+
+	asrt.True(CancelFilter{}.matches(o))
+	asrt.False(CancelFilter{}.matches(&model.OrderItemV5{}))
+	asrt.False(CancelFilter{TickerIDs: []int64{913243251}}.matches(o))
+	asrt.False(CancelFilter{Match: func(*model.OrderItemV5) bool { return false }}.matches(o))
+}
+
+// paperCancelServer serves paper orders whose status moves through `after`
+// once cancelled, one step per order listing.
+type paperCancelServer struct {
+	mu       sync.Mutex
+	status   map[string]string
+	after    map[string][]string
+	canceled map[string]bool
+}
+
+func (s *paperCancelServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := strings.Index(req.URL.Path, "/orderop/cancel/"); i >= 0 {
+		s.canceled[req.URL.Path[i+len("/orderop/cancel/"):]] = true
+		_, _ = io.WriteString(w, "{}")
+		return
+	}
+	status := req.URL.Query().Get("status")
+	orders := make([]string, 0)
+	for id := 1; id <= len(s.status); id++ {
+		key := fmt.Sprint(id)
+		if status == string(model.ALL) && s.canceled[key] && len(s.after[key]) > 0 {
+			s.status[key], s.after[key] = s.after[key][0], s.after[key][1:]
+		}
+		if status == string(model.ALL) || strings.EqualFold(status, s.status[key]) {
+			orders = append(orders, fmt.Sprintf(`{"orderId":"%s","status":"%s","totalQuantity":"1","createTime0":%d,"ticker":{"tickerId":1}}`,
+				key, s.status[key], 1677682800000-int64(id)))
+		}
+	}
+	_, _ = io.WriteString(w, "["+strings.Join(orders, ",")+"]")
+}
+
+func TestCancelAllVerifiesFinalStatus(t *testing.T) {
+	asrt := assert.New(t)
+	defer func(d time.Duration) { cancelVerifyDelay = d }(cancelVerifyDelay)
+	cancelVerifyDelay = time.Millisecond
+
+	server := &paperCancelServer{
+		status: map[string]string{"1": "Working", "2": "Working", "3": "Working", "4": "Working"},
+		after: map[string][]string{
+			"1": {"Pending Cancel", "Cancelled"},
+			"2": {"Filled"},
+			"3": {"Rejected"},
+		},
+		canceled: make(map[string]bool),
+	}
+	c := newTestClient(t, server)
+	results, err := c.CancelAll(context.Background(), TradingAccount{ID: 1, Paper: true}, CancelFilter{})
+	asrt.Error(err)
+	asrt.Len(results, 4)
+	byID := make(map[string]CancelResult)
+	for _, r := range results {
+		byID[r.OrderID] = r
+	}
+	asrt.True(byID["1"].Cancelled)
+	asrt.Empty(byID["1"].Err)
+	asrt.False(byID["2"].Cancelled, "a fill during the cancel is not a cancellation")
+	asrt.Equal("Filled", byID["2"].Status)
+	asrt.Error(byID["2"].Err)
+	asrt.False(byID["3"].Cancelled)
+	asrt.Error(byID["3"].Err)
+	asrt.False(byID["4"].Cancelled)
+	asrt.Equal("Working", byID["4"].Status)
+	asrt.Error(byID["4"].Err)
+}
+
+func TestCancelAllPaper(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	asrt.NotNil(c)
+
+	paperAccID, err := c.GetPaperTradeAccountID()
+	asrt.Empty(err)
+	asrt.NotEmpty(paperAccID)
+
+	tickerID, err := c.GetTickerID("SPY")
+	asrt.Empty(err)
+
+	_, err = c.PlacePaperOrder(paperAccID, model.PostStockOrderRequest{
+		Action:                    model.PtrOrderSide(model.BUY),
+		ComboType:                 model.PtrComboType("NORMAL"),
+		LmtPrice:                  model.PtrFloat64(1),
+		OrderType:                 model.PtrOrderType(model.LMT),
+		OutsideRegularTradingHour: model.PtrBool(false),
+		Quantity:                  model.PtrFloat64(1),
+		SerialId:                  model.PtrString(c.UUID),
+		TickerId:                  model.PtrInt64(tickerID),
+		TimeInForce:               model.PtrTif(model.DAY),
+	})
+	asrt.Empty(err)
+
+	results, err := c.CancelAll(context.Background(), TradingAccount{ID: paperAccID, Paper: true}, CancelFilter{TickerIDs: []int64{tickerID}})
+	asrt.Empty(err)
+	asrt.NotEmpty(results)
+	for _, r := range results {
+		asrt.True(r.Cancelled)
+		asrt.Empty(r.Err)
+	}
+}
@@ -11,8 +11,10 @@ import (
 	"net/http"
 	"net/url"
 	"quantfu.com/webull/client/internal"
+	"sync"
 	"time"
 
+	MQTT "github.com/eclipse/paho.mqtt.golang"
 	model "quantfu.com/webull/openapi"
 )
 
@@ -76,6 +78,10 @@ type Client struct {
 	TradeToken           string
 	TradeTokenExpiration time.Time
 
+	// tokenMu guards the tokens above while logging in or refreshing, so
+	// requests, including those of sub-clients, read a consistent session.
+	tokenMu sync.RWMutex
+
 	DeviceID string
 
 	httpClient         *http.Client
@@ -83,7 +89,32 @@ type Client struct {
 
 	sessionHeaders map[string]string
 
+	// parent is the client a sub-client from AccountRegistry.Client reads its
+	// tokens from on every request.
+	parent *Client
+
 	MdProvider MetaDataProvider
+
+	// PaperAccountID, when set, is the paper account used by helpers that do not
+	// take an account ID. See SelectPaperAccount.
+	PaperAccountID int64
+
+	// Risk, when set, checks every order before it is sent. See NewRiskManager.
+	Risk *RiskManager
+
+	// DryRun, when set, captures mutating calls instead of sending them. See NewDryRun.
+	DryRun *DryRun
+
+	// RateLimit, when set, paces every request. See NewRateLimiter.
+	RateLimit *RateLimiter
+
+	// streamingOptions holds the derivative IDs subscribed by
+	// ConnectStreamingOptionQuotes, whose messages become OptionQuoteEvents.
+	// quoteStream is the connection ConnectStreamingQuotes is reading, if any.
+	// Both are guarded by streamMu.
+	streamMu         sync.Mutex
+	streamingOptions map[int64]bool
+	quoteStream      MQTT.Client
 }
 
 // NewClient is a constructor for the Webull-Client client
@@ -176,7 +207,13 @@ func (c *Client) DeregisterCallback(topic string) error {
 // GetAndDecode retrieves from the endpoint and unmarshals resulting json into
 // the provided destination interface, which must be a pointer.
 func (c *Client) GetAndDecode(URL url.URL, dest interface{}, headers *map[string]string, urlValues *map[string]string) error {
-	if time.Now().After(c.AccessTokenExpiration) {
+	return c.GetAndDecodeWithContext(context.Background(), URL, dest, headers, urlValues)
+}
+
+// GetAndDecodeWithContext is GetAndDecode with a request bound to `ctx`.
+func (c *Client) GetAndDecodeWithContext(ctx context.Context, URL url.URL, dest interface{}, headers *map[string]string, urlValues *map[string]string) error {
+	session := c.applySession(headers)
+	if time.Now().After(session.accessExpiration) {
 		return &AuthExpiredError{}
 	}
 	v := url.Values{}
@@ -187,7 +224,7 @@ func (c *Client) GetAndDecode(URL url.URL, dest interface{}, headers *map[string
 	}
 	URL.RawQuery = v.Encode()
 
-	if req, err := http.NewRequest(http.MethodGet, URL.String(), nil); err != nil {
+	if req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL.String(), nil); err != nil {
 		return err
 	} else if req == nil {
 		return fmt.Errorf("unable to create request")
@@ -209,8 +246,9 @@ func (c *Client) GetAndDecode(URL url.URL, dest interface{}, headers *map[string
 // PostAndDecode retrieves from the endpoint and unmarshals resulting json into
 // the provided destination interface, which must be a pointer.
 func (c *Client) PostAndDecode(URL url.URL, dest interface{}, headers *map[string]string, urlValues *map[string]string, payload []byte) error {
-	if c.AccessToken != "" {
-		if time.Now().After(c.AccessTokenExpiration) {
+	session := c.applySession(headers)
+	if session.access != "" {
+		if time.Now().After(session.accessExpiration) {
 			return &AuthExpiredError{}
 		}
 	}
@@ -241,6 +279,45 @@ func (c *Client) PostAndDecode(URL url.URL, dest interface{}, headers *map[strin
 	}
 }
 
+// sessionTokens are the tokens a request is sent with.
+type sessionTokens struct {
+	access           string
+	accessExpiration time.Time
+	trade            string
+}
+
+// session reads the current tokens of the client owning the session, the
+// parent of a sub-client, under its lock.
+func (c *Client) session() sessionTokens {
+	owner := c
+	for owner.parent != nil {
+		owner = owner.parent
+	}
+	owner.tokenMu.RLock()
+	defer owner.tokenMu.RUnlock()
+	return sessionTokens{
+		access:           owner.AccessToken,
+		accessExpiration: owner.AccessTokenExpiration,
+		trade:            owner.TradeToken,
+	}
+}
+
+// applySession sets the token headers of a request about to be sent to the
+// current session tokens, which it returns.
+func (c *Client) applySession(headers *map[string]string) sessionTokens {
+	session := c.session()
+	if headers == nil {
+		return session
+	}
+	if _, ok := (*headers)[HeaderKeyAccessToken]; ok {
+		(*headers)[HeaderKeyAccessToken] = session.access
+	}
+	if _, ok := (*headers)[HeaderKeyTradeToken]; ok {
+		(*headers)[HeaderKeyTradeToken] = session.trade
+	}
+	return session
+}
+
 func parseAnything(data []byte) (output interface{}, err error) {
 	if err = json.Unmarshal(data, &output); err != nil {
 		return nil, fmt.Errorf("Unable to marshal body as interface")
@@ -251,7 +328,7 @@ func parseAnything(data []byte) (output interface{}, err error) {
 // ConnectWebsockets connects to a streaming API by Webull
 // NOTE: client still unstable
 func (c *Client) ConnectWebsockets(ctx context.Context, messageTypes []string, tickerIDs []string) (err error) {
-	err = c.ConnectStreamingQuotes(ctx, c.Username, c.HashedPassword, c.DeviceID, c.AccessToken, messageTypes, tickerIDs)
+	err = c.ConnectStreamingQuotes(ctx, c.Username, c.HashedPassword, c.DeviceID, c.session().access, messageTypes, tickerIDs)
 	return err
 }
 
@@ -260,6 +337,11 @@ func (c *Client) ConnectWebsockets(ctx context.Context, messageTypes []string, t
 // Last fallback is a plain interface.
 func (c *Client) DoAndDecode(req *http.Request, dest interface{}) (err error) {
 	var anyBody interface{}
+	if c.RateLimit != nil {
+		if err = c.RateLimit.Wait(req.Context()); err != nil {
+			return err
+		}
+	}
 	req.Header.Add("Content-Type", "application/json")
 	res, err := c.httpClient.Do(req)
 	if err != nil {
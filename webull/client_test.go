@@ -3,6 +3,9 @@ package webull
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 	"time"
@@ -69,3 +72,26 @@ func TestClient_RegisterCallback(t *testing.T) {
 	asrt.Empty(err)
 
 }
+
+// newTestClient returns a Client whose requests, whatever their host, are
+// answered by `handler` on a local httptest.Server.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+	return &Client{
+		httpClient:            &http.Client{Transport: &testServerTransport{target: target}},
+		AccessTokenExpiration: time.Now().Add(time.Hour),
+	}
+}
+
+// testServerTransport redirects every request to a test server.
+type testServerTransport struct {
+	target *url.URL
+}
+
+func (t *testServerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
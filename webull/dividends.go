@@ -1,14 +1,25 @@
 package webull
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	model "quantfu.com/webull/openapi"
 )
 
 // GetAccountDividends gets account `accountID` total dividends.
 func (c *Client) GetAccountDividends(accountID int64) (*model.GetDividendsResponse, error) {
+	return c.getAccountDividends(context.Background(), accountID)
+}
+
+// getAccountDividends is GetAccountDividends with a request bound to `ctx`.
+func (c *Client) getAccountDividends(ctx context.Context, accountID int64) (*model.GetDividendsResponse, error) {
 	var (
 		u, _        = url.Parse(TradeEndpoint + "/v2/account/" + strconv.FormatInt(accountID, 10) + "/dividends")
 		response    model.GetDividendsResponse
@@ -21,10 +32,190 @@ func (c *Client) GetAccountDividends(accountID int64) (*model.GetDividendsRespon
 
 	queryParams["direct"] = "in"
 
-	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
+	err := c.GetAndDecodeWithContext(ctx, *u, &response, &headersMap, &queryParams)
 	if err != nil {
 		return &response, err
 	}
 
 	return &response, err
 }
+
+// DividendRecord is a single dividend payment.
+type DividendRecord struct {
+	ID       string
+	TickerID int64
+	Symbol   string
+	ExDate   time.Time
+	PayDate  time.Time
+	// Shares held on the record date and the dividend paid on each.
+	Shares   float64
+	PerShare float64
+	Gross    float64
+	// Withholding is the tax withheld from the gross amount.
+	Withholding float64
+	Net         float64
+	Currency    string
+	Status      string
+}
+
+// DividendFilter selects dividend records by pay date and symbol. The zero value
+// selects every record.
+type DividendFilter struct {
+	From      time.Time
+	To        time.Time
+	Symbols   []string
+	TickerIDs []int64
+}
+
+// DividendTotals sums dividend amounts.
+type DividendTotals struct {
+	Gross       float64
+	Withholding float64
+	Net         float64
+	Count       int
+}
+
+// DividendYear aggregates a calendar year of dividends, as reported on a 1099-DIV.
+type DividendYear struct {
+	Year int
+	DividendTotals
+	BySymbol map[string]DividendTotals
+}
+
+// GetDividendRecords gets the dividends paid into account `accountID` matching
+// `filter`, oldest first. The records are those of the single list
+// GetAccountDividends returns; older dividends the endpoint doesn't list are
+// not paged in, so this is not necessarily the account's full history.
+func (c *Client) GetDividendRecords(ctx context.Context, accountID int64, filter DividendFilter) ([]DividendRecord, error) {
+	response, err := c.getAccountDividends(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]DividendRecord, 0, len(response.DividendList))
+	for _, d := range response.DividendList {
+		r, err := newDividendRecord(d)
+		if err != nil {
+			return nil, err
+		}
+		if filter.matches(r) {
+			records = append(records, r)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].PayDate.Before(records[j].PayDate) })
+	return records, nil
+}
+
+// AggregateDividendsByYear totals `records` per pay date year and symbol.
+func AggregateDividendsByYear(records []DividendRecord) []DividendYear {
+	byYear := make(map[int]*DividendYear)
+	for _, r := range records {
+		y := r.PayDate.Year()
+		year, ok := byYear[y]
+		if !ok {
+			year = &DividendYear{Year: y, BySymbol: make(map[string]DividendTotals)}
+			byYear[y] = year
+		}
+		year.add(r)
+		symbol := year.BySymbol[r.Symbol]
+		symbol.add(r)
+		year.BySymbol[r.Symbol] = symbol
+	}
+	years := make([]DividendYear, 0, len(byYear))
+	for _, y := range byYear {
+		years = append(years, *y)
+	}
+	sort.Slice(years, func(i, j int) bool { return years[i].Year < years[j].Year })
+	return years
+}
+
+func (t *DividendTotals) add(r DividendRecord) {
+	t.Gross += r.Gross
+	t.Withholding += r.Withholding
+	t.Net += r.Net
+	t.Count++
+}
+
+func (f DividendFilter) matches(r DividendRecord) bool {
+	if !f.From.IsZero() && r.PayDate.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && r.PayDate.After(f.To) {
+		return false
+	}
+	if len(f.Symbols) > 0 || len(f.TickerIDs) > 0 {
+		for _, s := range f.Symbols {
+			if strings.EqualFold(s, r.Symbol) {
+				return true
+			}
+		}
+		for _, id := range f.TickerIDs {
+			if id == r.TickerID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// newDividendRecord converts a dividend from the model, deriving whichever of
+// the gross, withholding and net amounts is missing.
+func newDividendRecord(d model.Dividend) (DividendRecord, error) {
+	r := DividendRecord{
+		ID:       d.GetId(),
+		TickerID: d.GetTickerId(),
+		Symbol:   d.Ticker.GetSymbol(),
+		Currency: d.GetCurrency(),
+		Status:   d.GetStatus(),
+	}
+	if r.TickerID == 0 {
+		r.TickerID = d.Ticker.GetTickerId()
+	}
+	var err error
+	if r.ExDate, err = dividendDate("exDate", d.ExDate); err != nil {
+		return r, fmt.Errorf("dividend %s: %s", r.ID, err.Error())
+	}
+	if r.PayDate, err = dividendDate("payDate", d.PayDate); err != nil {
+		return r, fmt.Errorf("dividend %s: %s", r.ID, err.Error())
+	}
+	for _, f := range []struct {
+		name  string
+		value *string
+		dest  *float64
+	}{
+		{"holding", d.Holding, &r.Shares},
+		{"dividendPerShare", d.DividendPerShare, &r.PerShare},
+		{"dividendAmount", d.DividendAmount, &r.Gross},
+		{"taxAmount", d.TaxAmount, &r.Withholding},
+		{"netAmount", d.NetAmount, &r.Net},
+	} {
+		if *f.dest, err = parseOptionalFloat(f.name, f.value); err != nil {
+			return r, fmt.Errorf("dividend %s: %s", r.ID, err.Error())
+		}
+	}
+	r.Withholding = math.Abs(r.Withholding)
+	switch {
+	case r.Gross == 0 && r.Net != 0:
+		r.Gross = r.Net + r.Withholding
+	case r.Net == 0 && r.Gross != 0:
+		r.Net = r.Gross - r.Withholding
+	}
+	if r.Gross == 0 && r.Shares != 0 {
+		r.Gross = r.Shares * r.PerShare
+		r.Net = r.Gross - r.Withholding
+	}
+	return r, nil
+}
+
+// dividendDate reads dividend date `name` in any format Timestamp accepts, or
+// the zero time when it is missing.
+func dividendDate(name string, s *string) (time.Time, error) {
+	var ts Timestamp
+	if s == nil {
+		return time.Time{}, nil
+	}
+	if err := ts.UnmarshalJSON([]byte(*s)); err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q", name, *s)
+	}
+	return ts.Time, nil
+}
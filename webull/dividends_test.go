@@ -1,8 +1,11 @@
 package webull
 
 import (
+	"context"
+	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	model "quantfu.com/webull/openapi"
@@ -29,3 +32,76 @@ func TestGetAccountDividends(t *testing.T) {
 	asrt.Empty(err)
 	asrt.NotEmpty(res)
 }
+
+// dividendsFixture is hand-written from model.GetDividendsResponse; no
+// response of the dividends endpoint has been captured yet.
+const dividendsFixture = `{"dividendList":[
+	{"id":"3","tickerId":913256135,"ticker":{"tickerId":913256135,"symbol":"AAPL"},"exDate":"2023-02-10","payDate":"2023-02-16",
+		"holding":"100","dividendPerShare":"0.23","dividendAmount":"23.00","taxAmount":"-2.30"},
+	{"id":"2","ticker":{"tickerId":913255598,"symbol":"SPY"},"exDate":"2022-12-16","payDate":"2023-01-31",
+		"netAmount":"15.50"},
+	{"id":"1","tickerId":913256135,"ticker":{"tickerId":913256135,"symbol":"AAPL"},"exDate":"2022-11-04","payDate":"2022-11-10",
+		"holding":"100","dividendPerShare":"0.23"}]}`
+
+func TestDividendRecords(t *testing.T) {
+	asrt := assert.New(t)
+	var response model.GetDividendsResponse
+	asrt.Empty(json.Unmarshal([]byte(dividendsFixture), &response))
+	records := make([]DividendRecord, 0)
+	for _, d := range response.DividendList {
+		r, err := newDividendRecord(d)
+		asrt.Nil(err)
+		records = append(records, r)
+	}
+	asrt.Len(records, 3)
+
+	asrt.Equal("3", records[0].ID)
+	asrt.Equal(23.0, records[0].Gross)
+	asrt.Equal(2.3, records[0].Withholding)
+	asrt.InDelta(20.7, records[0].Net, 1e-9)
+	asrt.Equal(time.Date(2023, 2, 16, 0, 0, 0, 0, time.UTC), records[0].PayDate)
+	asrt.Equal("SPY", records[1].Symbol)
+	asrt.Equal(int64(913255598), records[1].TickerID)
+	asrt.Equal(15.5, records[1].Gross)
+	asrt.InDelta(23.0, records[2].Net, 1e-9)
+
+	filter := DividendFilter{From: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Symbols: []string{"aapl"}}
+	asrt.True(filter.matches(records[0]))
+	asrt.False(filter.matches(records[1]))
+	asrt.False(filter.matches(records[2]))
+
+	years := AggregateDividendsByYear(records)
+	asrt.Len(years, 2)
+	asrt.Equal(2022, years[0].Year)
+	asrt.Equal(2023, years[1].Year)
+	asrt.Equal(2, years[1].Count)
+	asrt.InDelta(38.5, years[1].Gross, 1e-9)
+	asrt.InDelta(36.2, years[1].Net, 1e-9)
+	asrt.InDelta(20.7, years[1].BySymbol["AAPL"].Net, 1e-9)
+
+	amount, date := "n/a", "someday"
+	_, err := newDividendRecord(model.Dividend{Id: model.PtrString("4"), NetAmount: &amount})
+	asrt.NotNil(err)
+	_, err = newDividendRecord(model.Dividend{Id: model.PtrString("5"), PayDate: &date})
+	asrt.NotNil(err)
+}
+
+func TestGetDividendRecords(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	acc, err := c.GetAccountID()
+	asrt.Empty(err)
+	records, err := c.GetDividendRecords(context.Background(), acc, DividendFilter{})
+	asrt.Empty(err)
+	asrt.NotNil(records)
+}
@@ -0,0 +1,77 @@
+package webull
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+func TestDryRunPlaceOrderV5(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+
+	var logged []DryRunRecord
+	c.DryRun = NewDryRun()
+	c.DryRun.Log = func(r DryRunRecord) { logged = append(logged, r) }
+
+	resp, err := c.PlaceOrderV5(1, newTestOrder(model.BUY, 1, 10, 913256135))
+	asrt.Empty(err)
+	asrt.NotEmpty(resp.GetOrderId())
+	asrt.Len(logged, 1)
+	asrt.Equal("PlaceOrderV5", logged[0].Method)
+	asrt.Contains(string(logged[0].Payload), "913256135")
+
+	ok, err := c.CancelOrderV5(1, 42)
+	asrt.Empty(err)
+	asrt.True(ok)
+	asrt.Len(c.DryRun.Records(), 2)
+}
+
+func TestDryRunValidatesOrder(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	c.DryRun = NewDryRun()
+	c.DryRun.Log = func(DryRunRecord) {}
+
+	input := newTestOrder(model.BUY, 1, 10, 913256135)
+	input.LmtPrice = nil
+	_, err = c.PlaceOrderV5(1, input)
+	asrt.Error(err)
+
+	input = newTestOrder(model.BUY, 0, 10, 913256135)
+	_, err = c.PlaceOrderV5(1, input)
+	asrt.Error(err)
+
+	input = newTestOrder(model.BUY, 1, 10, 913256135)
+	input.OrderType = model.PtrOrderType(model.STP)
+	_, err = c.PlaceOrderV5(1, input)
+	asrt.Error(err)
+	input.AuxPrice = model.PtrFloat64(9)
+	_, err = c.PlaceOrderV5(1, input)
+	asrt.Empty(err)
+	asrt.Len(c.DryRun.Records(), 1)
+}
+
+func TestDryRunRecordsRisk(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	c.DryRun = NewDryRun()
+	c.DryRun.Log = func(DryRunRecord) {}
+	NewRiskManager(c, RiskLimits{MaxPositionPerSymbol: 10})
+
+	resp, err := c.PlaceOrderV5(1, newTestOrder(model.BUY, 8, 10, 913256135))
+	asrt.Empty(err)
+	_, err = c.PlaceOrderV5(1, newTestOrder(model.BUY, 8, 10, 913256135))
+	asrt.Equal(RiskRulePosition, err.(*RiskError).Rule)
+
+	orderID, _ := strconv.ParseInt(resp.GetOrderId(), 10, 64)
+	_, err = c.CancelOrderV5(1, orderID)
+	asrt.Empty(err)
+	_, err = c.PlaceOrderV5(1, newTestOrder(model.BUY, 8, 10, 913256135))
+	asrt.Empty(err)
+}
@@ -8,4 +8,7 @@ const (
 	Field_CryptoBuyingPower    = "cryptoBuyingPower"
 	Field_OptionBuyingPower    = "optionBuyingPower"
 	Field_UsableCash           = "usableCash"
+	Field_NetLiquidation       = "netLiquidation"
+	Field_UnrealizedProfitLoss = "unrealizedProfitLoss"
+	Field_TotalCost            = "totalCost"
 )
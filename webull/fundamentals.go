@@ -0,0 +1,502 @@
+package webull
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// StatementKind is a financial statement.
+type StatementKind string
+
+// Financial statements
+const (
+	IncomeStatement   StatementKind = "incomestatement"
+	BalanceSheet      StatementKind = "balancesheet"
+	CashFlowStatement StatementKind = "cashflow"
+)
+
+// ReportPeriod is the length of a reporting period.
+type ReportPeriod string
+
+// Reporting periods
+const (
+	Annual    ReportPeriod = "101"
+	Quarterly ReportPeriod = "102"
+)
+
+// FinancialPeriod is one reporting period of a statement. Only the line items
+// of the statement's kind are set: Income, Balance or CashFlow.
+type FinancialPeriod struct {
+	FiscalYear int
+	// FiscalQuarter is 1 to 4, or 0 for a full year.
+	FiscalQuarter int
+	EndDate       time.Time
+	ReportDate    time.Time
+	Currency      string
+	Income        IncomeStatementItems
+	Balance       BalanceSheetItems
+	CashFlow      CashFlowItems
+	// Values holds every line item as Webull keys it, including those without
+	// a field above. DiffPeriods compares these.
+	Values map[string]float64
+}
+
+// IncomeStatementItems are the main line items of an income statement.
+type IncomeStatementItems struct {
+	Revenue          float64
+	CostOfRevenue    float64
+	GrossProfit      float64
+	OperatingExpense float64
+	OperatingIncome  float64
+	NetIncome        float64
+	EPS              float64
+	DilutedEPS       float64
+}
+
+// BalanceSheetItems are the main line items of a balance sheet.
+type BalanceSheetItems struct {
+	TotalAssets        float64
+	CurrentAssets      float64
+	Cash               float64
+	TotalLiabilities   float64
+	CurrentLiabilities float64
+	TotalDebt          float64
+	TotalEquity        float64
+}
+
+// CashFlowItems are the main line items of a cash flow statement.
+type CashFlowItems struct {
+	OperatingCashFlow  float64
+	InvestingCashFlow  float64
+	FinancingCashFlow  float64
+	CapitalExpenditure float64
+	FreeCashFlow       float64
+}
+
+// Label names the period, e.g. "2023 Q1" or "2022".
+func (p FinancialPeriod) Label() string {
+	if p.FiscalQuarter == 0 {
+		return strconv.Itoa(p.FiscalYear)
+	}
+	return fmt.Sprintf("%d Q%d", p.FiscalYear, p.FiscalQuarter)
+}
+
+// FinancialStatement is a statement over several periods, oldest first.
+type FinancialStatement struct {
+	TickerID int64
+	Kind     StatementKind
+	Period   ReportPeriod
+	Periods  []FinancialPeriod
+}
+
+// Latest returns the most recent period.
+func (s *FinancialStatement) Latest() (FinancialPeriod, bool) {
+	if len(s.Periods) == 0 {
+		return FinancialPeriod{}, false
+	}
+	return s.Periods[len(s.Periods)-1], true
+}
+
+// Find returns the period of fiscal `year` and `quarter`, 0 for the full year.
+func (s *FinancialStatement) Find(year, quarter int) (FinancialPeriod, bool) {
+	for _, p := range s.Periods {
+		if p.FiscalYear == year && p.FiscalQuarter == quarter {
+			return p, true
+		}
+	}
+	return FinancialPeriod{}, false
+}
+
+// Series returns line item `key` over every period, oldest first.
+func (s *FinancialStatement) Series(key string) []float64 {
+	values := make([]float64, len(s.Periods))
+	for i, p := range s.Periods {
+		values[i] = p.Values[key]
+	}
+	return values
+}
+
+// PeriodDiff is the change in a line item between two periods.
+type PeriodDiff struct {
+	Key    string
+	From   float64
+	To     float64
+	Change float64
+	// ChangePercent is Change as a fraction of the absolute From value, or NaN
+	// when From is zero.
+	ChangePercent float64
+}
+
+// DiffPeriods compares every line item of `from` and `to`, ordered by key.
+// Items missing from one period are compared against zero.
+func DiffPeriods(from, to FinancialPeriod) []PeriodDiff {
+	keys := make([]string, 0, len(to.Values))
+	for k := range from.Values {
+		keys = append(keys, k)
+	}
+	for k := range to.Values {
+		if _, ok := from.Values[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	diffs := make([]PeriodDiff, 0, len(keys))
+	for _, k := range keys {
+		d := PeriodDiff{Key: k, From: from.Values[k], To: to.Values[k]}
+		d.Change = d.To - d.From
+		d.ChangePercent = math.NaN()
+		if d.From != 0 {
+			d.ChangePercent = d.Change / math.Abs(d.From)
+		}
+		diffs = append(diffs, d)
+	}
+	return diffs
+}
+
+// KeyRatios are valuation and quality ratios of a company.
+type KeyRatios struct {
+	MarketCap     float64
+	PE            float64
+	ForwardPE     float64
+	PB            float64
+	PS            float64
+	EPS           float64
+	DividendYield float64
+	ROE           float64
+	ROA           float64
+	GrossMargin   float64
+	NetMargin     float64
+	DebtToEquity  float64
+	CurrentRatio  float64
+	Beta          float64
+}
+
+// EarningsPeriod is reported or estimated earnings for a fiscal quarter.
+type EarningsPeriod struct {
+	FiscalYear    int
+	FiscalQuarter int
+	ReportDate    time.Time
+	EPS           float64
+	EPSEstimate   float64
+	Revenue       float64
+	// RevenueEstimate is the consensus estimate before the report.
+	RevenueEstimate float64
+}
+
+// Surprise is the reported EPS above the estimate, as a fraction of the
+// absolute estimate, or NaN without an estimate.
+func (e EarningsPeriod) Surprise() float64 {
+	if e.EPSEstimate == 0 {
+		return math.NaN()
+	}
+	return (e.EPS - e.EPSEstimate) / math.Abs(e.EPSEstimate)
+}
+
+// Earnings is the earnings history and estimates of a company.
+type Earnings struct {
+	// History is reported quarters, oldest first.
+	History []EarningsPeriod
+	// Estimates is upcoming quarters, soonest first.
+	Estimates []EarningsPeriod
+}
+
+// GetFinancialStatement gets up to `limit` `period` reports of statement `kind`
+// for ticker `tickerID`.
+func (c *Client) GetFinancialStatement(tickerID int64, kind StatementKind, period ReportPeriod, limit int) (*FinancialStatement, error) {
+	var (
+		u, _        = url.Parse(BrokerQuotesGWEndpointV + "/information/financial/" + string(kind))
+		response    financialStatementResponse
+		headersMap  = make(map[string]string)
+		queryParams = make(map[string]string)
+	)
+
+	headersMap[HeaderKeyAccessToken] = c.AccessToken
+	headersMap[HeaderKeyDeviceID] = c.DeviceID
+
+	queryParams["tickerId"] = strconv.FormatInt(tickerID, 10)
+	queryParams["type"] = string(period)
+	queryParams["fiscalPeriod"] = "0,1,2,3,4"
+	queryParams["limit"] = strconv.Itoa(limit)
+
+	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
+	if err != nil {
+		return nil, err
+	}
+	periods, err := newFinancialPeriods(kind, response)
+	if err != nil {
+		return nil, err
+	}
+	return &FinancialStatement{
+		TickerID: tickerID,
+		Kind:     kind,
+		Period:   period,
+		Periods:  periods,
+	}, nil
+}
+
+// GetKeyRatios gets the key ratios of ticker `tickerID` from its fundamentals,
+// the endpoint of GetStockFundamentals.
+func (c *Client) GetKeyRatios(tickerID int64) (*KeyRatios, error) {
+	var (
+		u, _       = url.Parse(QuotesEndpoint + "/securities/financial/index/" + strconv.FormatInt(tickerID, 10))
+		response   keyRatiosResponse
+		headersMap = make(map[string]string)
+	)
+
+	headersMap[HeaderKeyAccessToken] = c.AccessToken
+	headersMap[HeaderKeyDeviceID] = c.DeviceID
+
+	err := c.GetAndDecode(*u, &response, &headersMap, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newKeyRatios(response)
+}
+
+// GetEarnings gets the earnings history and estimates of ticker `tickerID`.
+func (c *Client) GetEarnings(tickerID int64) (*Earnings, error) {
+	var (
+		u, _        = url.Parse(BrokerQuotesGWEndpointV + "/information/financial/earnings")
+		response    earningsResponse
+		headersMap  = make(map[string]string)
+		queryParams = make(map[string]string)
+	)
+
+	headersMap[HeaderKeyAccessToken] = c.AccessToken
+	headersMap[HeaderKeyDeviceID] = c.DeviceID
+
+	queryParams["tickerId"] = strconv.FormatInt(tickerID, 10)
+
+	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
+	if err != nil {
+		return nil, err
+	}
+	return newEarnings(response)
+}
+
+// financialStatementResponse is the body of the statement endpoints.
+type financialStatementResponse struct {
+	Data []struct {
+		FiscalYear *Number `json:"fiscalYear"`
+		// FiscalPeriod is the quarter, or 0 for a full year.
+		FiscalPeriod *Number   `json:"fiscalPeriod"`
+		EndDate      Timestamp `json:"endDate"`
+		ReportDate   Timestamp `json:"reportDate"`
+		Currency     string    `json:"currency"`
+		Items        []struct {
+			Key   string  `json:"key"`
+			Value *Number `json:"value"`
+		} `json:"items"`
+	} `json:"data"`
+}
+
+// keyRatiosResponse is the part of the fundamentals body holding key ratios.
+type keyRatiosResponse struct {
+	MarketValue     *Number `json:"marketValue"`
+	PeTtm           *Number `json:"peTtm"`
+	ForwardPe       *Number `json:"forwardPe"`
+	Pb              *Number `json:"pb"`
+	Ps              *Number `json:"ps"`
+	EpsTtm          *Number `json:"epsTtm"`
+	Yield           *Number `json:"yield"`
+	Roe             *Number `json:"roe"`
+	Roa             *Number `json:"roa"`
+	GrossProfitRate *Number `json:"grossProfitRate"`
+	NetProfitRate   *Number `json:"netProfitRate"`
+	DebtToEquity    *Number `json:"debtToEquity"`
+	CurrentRatio    *Number `json:"currentRatio"`
+	Beta            *Number `json:"beta"`
+}
+
+// earningsResponse is the body of the earnings endpoint.
+type earningsResponse struct {
+	History   []earningsPeriodResponse `json:"history"`
+	Estimates []earningsPeriodResponse `json:"estimates"`
+}
+
+type earningsPeriodResponse struct {
+	FiscalYear      *Number   `json:"fiscalYear"`
+	FiscalPeriod    *Number   `json:"fiscalPeriod"`
+	ReleaseDate     Timestamp `json:"releaseDate"`
+	Eps             *Number   `json:"eps"`
+	EpsEstimate     *Number   `json:"epsEstimate"`
+	Revenue         *Number   `json:"revenue"`
+	RevenueEstimate *Number   `json:"revenueEstimate"`
+}
+
+// newFinancialPeriods reads the periods of statement `kind`, oldest first.
+// Every period must name its fiscal year and period and report line items.
+func newFinancialPeriods(kind StatementKind, response financialStatementResponse) ([]FinancialPeriod, error) {
+	periods := make([]FinancialPeriod, 0, len(response.Data))
+	for _, d := range response.Data {
+		year, quarter, err := fiscalPeriod(d.FiscalYear, d.FiscalPeriod, 0)
+		if err != nil {
+			return nil, err
+		}
+		p := FinancialPeriod{
+			FiscalYear:    year,
+			FiscalQuarter: quarter,
+			EndDate:       d.EndDate.Time,
+			ReportDate:    d.ReportDate.Time,
+			Currency:      d.Currency,
+			Values:        make(map[string]float64, len(d.Items)),
+		}
+		if len(d.Items) == 0 {
+			return nil, fmt.Errorf("%s period %s has no line items", kind, p.Label())
+		}
+		for _, item := range d.Items {
+			if item.Key == "" || item.Value == nil {
+				return nil, fmt.Errorf("%s period %s has a line item without key or value", kind, p.Label())
+			}
+			p.Values[item.Key] = item.Value.Float64()
+		}
+		p.setItems(kind)
+		periods = append(periods, p)
+	}
+	sort.SliceStable(periods, func(i, j int) bool {
+		a, b := periods[i], periods[j]
+		if !a.EndDate.IsZero() && !b.EndDate.IsZero() {
+			return a.EndDate.Before(b.EndDate)
+		}
+		if a.FiscalYear != b.FiscalYear {
+			return a.FiscalYear < b.FiscalYear
+		}
+		return a.FiscalQuarter < b.FiscalQuarter
+	})
+	return periods, nil
+}
+
+// fiscalPeriod reads a required fiscal year and quarter, the quarter being
+// at least `minQuarter`.
+func fiscalPeriod(year, quarter *Number, minQuarter int) (int, int, error) {
+	switch {
+	case year == nil || *year <= 0:
+		return 0, 0, fmt.Errorf("period is missing fiscalYear")
+	case quarter == nil:
+		return 0, 0, fmt.Errorf("period of %d is missing fiscalPeriod", int(*year))
+	case int(*quarter) < minQuarter || *quarter > 4:
+		return 0, 0, fmt.Errorf("period of %d has invalid fiscalPeriod %v", int(*year), quarter.Float64())
+	}
+	return int(*year), int(*quarter), nil
+}
+
+// setItems fills the typed line items of statement `kind` from p.Values. Line
+// items the company doesn't report are zero.
+func (p *FinancialPeriod) setItems(kind StatementKind) {
+	v := p.Values
+	switch kind {
+	case IncomeStatement:
+		p.Income = IncomeStatementItems{
+			Revenue:          v["totalRevenue"],
+			CostOfRevenue:    v["costOfRevenue"],
+			GrossProfit:      v["grossProfit"],
+			OperatingExpense: v["operatingExpense"],
+			OperatingIncome:  v["operatingIncome"],
+			NetIncome:        v["netIncome"],
+			EPS:              v["eps"],
+			DilutedEPS:       v["dilutedEps"],
+		}
+	case BalanceSheet:
+		p.Balance = BalanceSheetItems{
+			TotalAssets:        v["totalAssets"],
+			CurrentAssets:      v["totalCurrentAssets"],
+			Cash:               v["cashAndCashEquivalents"],
+			TotalLiabilities:   v["totalLiabilities"],
+			CurrentLiabilities: v["totalCurrentLiabilities"],
+			TotalDebt:          v["totalDebt"],
+			TotalEquity:        v["totalEquity"],
+		}
+	case CashFlowStatement:
+		p.CashFlow = CashFlowItems{
+			OperatingCashFlow:  v["operatingCashFlow"],
+			InvestingCashFlow:  v["investingCashFlow"],
+			FinancingCashFlow:  v["financingCashFlow"],
+			CapitalExpenditure: v["capitalExpenditure"],
+			FreeCashFlow:       v["freeCashFlow"],
+		}
+		if _, ok := v["freeCashFlow"]; !ok {
+			// capital expenditure is reported as a negative amount
+			p.CashFlow.FreeCashFlow = p.CashFlow.OperatingCashFlow - math.Abs(p.CashFlow.CapitalExpenditure)
+		}
+	}
+}
+
+// newKeyRatios reads key ratios. Ratios Webull doesn't report for the company
+// are zero, but a body without any of them is an error.
+func newKeyRatios(r keyRatiosResponse) (*KeyRatios, error) {
+	found := false
+	value := func(n *Number) float64 {
+		found = found || n != nil
+		return optionalNumber(n)
+	}
+	ratios := &KeyRatios{
+		MarketCap:     value(r.MarketValue),
+		PE:            value(r.PeTtm),
+		ForwardPE:     value(r.ForwardPe),
+		PB:            value(r.Pb),
+		PS:            value(r.Ps),
+		EPS:           value(r.EpsTtm),
+		DividendYield: value(r.Yield),
+		ROE:           value(r.Roe),
+		ROA:           value(r.Roa),
+		GrossMargin:   value(r.GrossProfitRate),
+		NetMargin:     value(r.NetProfitRate),
+		DebtToEquity:  value(r.DebtToEquity),
+		CurrentRatio:  value(r.CurrentRatio),
+		Beta:          value(r.Beta),
+	}
+	if !found {
+		return nil, fmt.Errorf("fundamentals carry no key ratios")
+	}
+	return ratios, nil
+}
+
+// newEarnings reads the earnings history, each quarter requiring its reported
+// EPS, and the estimates, each requiring an EPS estimate.
+func newEarnings(r earningsResponse) (*Earnings, error) {
+	history, err := newEarningsPeriods(r.History, true)
+	if err != nil {
+		return nil, err
+	}
+	estimates, err := newEarningsPeriods(r.Estimates, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Earnings{History: history, Estimates: estimates}, nil
+}
+
+func newEarningsPeriods(list []earningsPeriodResponse, reported bool) ([]EarningsPeriod, error) {
+	periods := make([]EarningsPeriod, 0, len(list))
+	for _, e := range list {
+		year, quarter, err := fiscalPeriod(e.FiscalYear, e.FiscalPeriod, 1)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case reported && e.Eps == nil:
+			return nil, fmt.Errorf("earnings of %d Q%d are missing eps", year, quarter)
+		case !reported && e.EpsEstimate == nil:
+			return nil, fmt.Errorf("earnings estimate of %d Q%d is missing epsEstimate", year, quarter)
+		}
+		periods = append(periods, EarningsPeriod{
+			FiscalYear:      year,
+			FiscalQuarter:   quarter,
+			ReportDate:      e.ReleaseDate.Time,
+			EPS:             optionalNumber(e.Eps),
+			EPSEstimate:     optionalNumber(e.EpsEstimate),
+			Revenue:         optionalNumber(e.Revenue),
+			RevenueEstimate: optionalNumber(e.RevenueEstimate),
+		})
+	}
+	sort.SliceStable(periods, func(i, j int) bool {
+		if periods[i].FiscalYear != periods[j].FiscalYear {
+			return periods[i].FiscalYear < periods[j].FiscalYear
+		}
+		return periods[i].FiscalQuarter < periods[j].FiscalQuarter
+	})
+	return periods, nil
+}
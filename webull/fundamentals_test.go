@@ -0,0 +1,161 @@
+package webull
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+// incomeStatementFixture is hand-written, not captured, in the shape the
+// statement endpoints are read in.
+const incomeStatementFixture = `{"data":[
+	{"fiscalYear":2023,"fiscalPeriod":1,"endDate":"2022-12-31","reportDate":"2023-02-02","currency":"USD",
+		"items":[{"key":"totalRevenue","value":"117154000000"},{"key":"netIncome","value":"29998000000"}]},
+	{"fiscalYear":2022,"fiscalPeriod":4,"endDate":"2022-09-24","currency":"USD",
+		"items":[{"key":"totalRevenue","value":"90146000000"},{"key":"netIncome","value":"20721000000"},{"key":"operatingExpense","value":"13201000000"}]}]}`
+
+func decodeStatement(t *testing.T, kind StatementKind, body string) ([]FinancialPeriod, error) {
+	var response financialStatementResponse
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatal(err)
+	}
+	return newFinancialPeriods(kind, response)
+}
+
+func TestFinancialStatement(t *testing.T) {
+	asrt := assert.New(t)
+	periods, err := decodeStatement(t, IncomeStatement, incomeStatementFixture)
+	asrt.Empty(err)
+	s := &FinancialStatement{Kind: IncomeStatement, Period: Quarterly, Periods: periods}
+	asrt.Len(s.Periods, 2)
+
+	q4 := s.Periods[0]
+	asrt.Equal("2022 Q4", q4.Label())
+	asrt.Equal(time.Date(2022, 9, 24, 0, 0, 0, 0, time.UTC), q4.EndDate)
+	asrt.Equal(9.0146e10, q4.Values["totalRevenue"])
+	asrt.Equal(9.0146e10, q4.Income.Revenue)
+	asrt.Equal(1.3201e10, q4.Income.OperatingExpense)
+	asrt.Equal(BalanceSheetItems{}, q4.Balance)
+
+	latest, ok := s.Latest()
+	asrt.True(ok)
+	asrt.Equal("2023 Q1", latest.Label())
+	asrt.Equal(time.Date(2023, 2, 2, 0, 0, 0, 0, time.UTC), latest.ReportDate)
+	asrt.Equal(2.9998e10, latest.Income.NetIncome)
+	found, ok := s.Find(2023, 1)
+	asrt.True(ok)
+	asrt.Equal(latest, found)
+	_, ok = s.Find(2021, 0)
+	asrt.False(ok)
+	asrt.Equal([]float64{2.0721e10, 2.9998e10}, s.Series("netIncome"))
+
+	diffs := DiffPeriods(q4, latest)
+	asrt.Len(diffs, 3)
+	asrt.Equal("netIncome", diffs[0].Key)
+	asrt.InDelta(9.277e9, diffs[0].Change, 1)
+	asrt.InDelta(9.277e9/2.0721e10, diffs[0].ChangePercent, 1e-9)
+	asrt.Equal("operatingExpense", diffs[1].Key)
+	asrt.Equal(0.0, diffs[1].To, "missing items compare against zero")
+	asrt.Equal("totalRevenue", diffs[2].Key)
+	asrt.True(math.IsNaN(DiffPeriods(FinancialPeriod{}, latest)[0].ChangePercent))
+}
+
+func TestFinancialStatementItems(t *testing.T) {
+	asrt := assert.New(t)
+	balance, err := decodeStatement(t, BalanceSheet, `{"data":[{"fiscalYear":2022,"fiscalPeriod":0,"items":[
+		{"key":"totalAssets","value":"352755000000"},{"key":"totalLiabilities","value":"302083000000"},{"key":"totalEquity","value":"50672000000"}]}]}`)
+	asrt.Empty(err)
+	asrt.Len(balance, 1)
+	asrt.Equal("2022", balance[0].Label())
+	asrt.Equal(3.52755e11, balance[0].Balance.TotalAssets)
+	asrt.Equal(5.0672e10, balance[0].Balance.TotalEquity)
+	asrt.Equal(IncomeStatementItems{}, balance[0].Income)
+
+	cash, err := decodeStatement(t, CashFlowStatement, `{"data":[{"fiscalYear":2022,"fiscalPeriod":0,"items":[
+		{"key":"operatingCashFlow","value":"122151000000"},{"key":"capitalExpenditure","value":"-10708000000"}]}]}`)
+	asrt.Empty(err)
+	asrt.Equal(1.22151e11, cash[0].CashFlow.OperatingCashFlow)
+	asrt.InDelta(1.11443e11, cash[0].CashFlow.FreeCashFlow, 1)
+
+	for _, body := range []string{
+		`{"data":[{"fiscalPeriod":1,"items":[{"key":"netIncome","value":"1"}]}]}`,
+		`{"data":[{"fiscalYear":2022,"items":[{"key":"netIncome","value":"1"}]}]}`,
+		`{"data":[{"fiscalYear":2022,"fiscalPeriod":5,"items":[{"key":"netIncome","value":"1"}]}]}`,
+		`{"data":[{"fiscalYear":2022,"fiscalPeriod":1}]}`,
+		`{"data":[{"fiscalYear":2022,"fiscalPeriod":1,"items":[{"key":"netIncome"}]}]}`,
+	} {
+		_, err := decodeStatement(t, IncomeStatement, body)
+		asrt.Error(err, body)
+	}
+}
+
+func TestKeyRatiosAndEarnings(t *testing.T) {
+	asrt := assert.New(t)
+	var fundamentals keyRatiosResponse
+	asrt.Empty(json.Unmarshal([]byte(`{"marketValue":"2380000000000","peTtm":"25.4","pb":"44.1","epsTtm":"5.89","yield":"0.0061","roe":"1.47","beta":"1.28"}`), &fundamentals))
+	ratios, err := newKeyRatios(fundamentals)
+	asrt.Empty(err)
+	asrt.Equal(2.38e12, ratios.MarketCap)
+	asrt.Equal(25.4, ratios.PE)
+	asrt.Equal(5.89, ratios.EPS)
+	asrt.Equal(0.0061, ratios.DividendYield)
+	asrt.Equal(1.28, ratios.Beta)
+	_, err = newKeyRatios(keyRatiosResponse{})
+	asrt.Error(err)
+
+	var response earningsResponse
+	asrt.Empty(json.Unmarshal([]byte(`{
+		"history":[
+			{"fiscalYear":2023,"fiscalPeriod":1,"releaseDate":"2023-02-02","eps":"1.88","epsEstimate":"1.94","revenue":"117154000000"},
+			{"fiscalYear":2022,"fiscalPeriod":4,"releaseDate":"2022-10-27","eps":"1.29","epsEstimate":"1.27"}],
+		"estimates":[{"fiscalYear":2023,"fiscalPeriod":2,"releaseDate":"2023-05-04","epsEstimate":"1.43"}]}`), &response))
+	earnings, err := newEarnings(response)
+	asrt.Empty(err)
+	asrt.Len(earnings.History, 2)
+	asrt.Equal(4, earnings.History[0].FiscalQuarter)
+	asrt.InDelta(0.02/1.27, earnings.History[0].Surprise(), 1e-9)
+	asrt.InDelta(-0.06/1.94, earnings.History[1].Surprise(), 1e-9)
+	asrt.Len(earnings.Estimates, 1)
+	asrt.Equal(time.Date(2023, 5, 4, 0, 0, 0, 0, time.UTC), earnings.Estimates[0].ReportDate)
+	asrt.True(math.IsNaN(EarningsPeriod{EPS: 1}.Surprise()))
+
+	for _, body := range []string{
+		`{"history":[{"fiscalYear":2023,"fiscalPeriod":1,"epsActual":"1.88"}]}`,
+		`{"history":[{"fiscalYear":2023,"fiscalPeriod":0,"eps":"1.88"}]}`,
+		`{"estimates":[{"fiscalYear":2023,"fiscalPeriod":2}]}`,
+	} {
+		var response earningsResponse
+		asrt.Empty(json.Unmarshal([]byte(body), &response))
+		_, err := newEarnings(response)
+		asrt.Error(err, body)
+	}
+}
+
+func TestGetFinancialStatement(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	for _, kind := range []StatementKind{IncomeStatement, BalanceSheet, CashFlowStatement} {
+		s, err := c.GetFinancialStatement(913256135, kind, Annual, 4)
+		asrt.Empty(err)
+		asrt.NotEmpty(s.Periods)
+	}
+	_, err = c.GetKeyRatios(913256135)
+	asrt.Empty(err)
+	_, err = c.GetEarnings(913256135)
+	asrt.Empty(err)
+}
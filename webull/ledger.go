@@ -0,0 +1,513 @@
+package webull
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	model "quantfu.com/webull/openapi"
+)
+
+// LotMethod selects which lots a closing fill is matched against.
+type LotMethod int
+
+// Lot methods
+const (
+	LotFIFO LotMethod = iota
+	LotLIFO
+	LotAverageCost
+)
+
+// QuoteSource prices tickers for mark to market. Client, PaperSimulator and
+// every Broker implement it.
+type QuoteSource interface {
+	GetRealtimeStockQuote(tickerID int64) (*model.GetStockQuoteResponse, error)
+}
+
+// Fill is a single execution ingested by a Ledger.
+type Fill struct {
+	OrderID  string
+	TickerID int64
+	Symbol   string
+	Action   model.OrderSide
+	Quantity decimal.Decimal
+	Price    decimal.Decimal
+	// Commission is added to the cost of buys and taken from the proceeds of sells.
+	Commission decimal.Decimal
+	Time       time.Time
+}
+
+// Lot is an open tax lot. Short lots have a negative quantity.
+type Lot struct {
+	Quantity decimal.Decimal
+	Price    decimal.Decimal
+	Time     time.Time
+}
+
+// LedgerPosition is a position rebuilt from fills. Amounts are decimals, like
+// the broker positions of a Portfolio it is reconciled with.
+type LedgerPosition struct {
+	TickerID    int64
+	Symbol      string
+	Quantity    decimal.Decimal
+	Lots        []Lot
+	RealizedPnL decimal.Decimal
+	LastPrice   decimal.Decimal
+}
+
+// CostBasis is the total cost of the open lots.
+func (p LedgerPosition) CostBasis() decimal.Decimal {
+	cost := decimal.Zero
+	for _, l := range p.Lots {
+		cost = cost.Add(l.Quantity.Mul(l.Price))
+	}
+	return cost
+}
+
+// AvgCost is the cost basis per share.
+func (p LedgerPosition) AvgCost() decimal.Decimal {
+	if p.Quantity.IsZero() {
+		return decimal.Zero
+	}
+	return p.CostBasis().Div(p.Quantity)
+}
+
+// MarketValue at the last price.
+func (p LedgerPosition) MarketValue() decimal.Decimal {
+	return p.Quantity.Mul(p.LastPrice)
+}
+
+// UnrealizedPnL at the last price, zero until the position is priced.
+func (p LedgerPosition) UnrealizedPnL() decimal.Decimal {
+	if p.LastPrice.IsZero() {
+		return decimal.Zero
+	}
+	return p.MarketValue().Sub(p.CostBasis())
+}
+
+// Ledger rebuilds positions, cost basis and P&L from fills, whether fetched
+// from live or paper orders or streamed from an OrderTracker or PaperSimulator.
+type Ledger struct {
+	Method LotMethod
+
+	mu        sync.Mutex
+	positions map[int64]*LedgerPosition
+	// booked is what was booked per order, so the same order read again, or
+	// fed through several of AddOrder, AddOrderEvent and AddOrderFill, only
+	// books its new fills.
+	booked map[string]*orderBooking
+}
+
+// orderBooking is the cumulative quantity and cost booked for an order, and
+// the quantity of the individual fills of it seen by AddOrderFill.
+type orderBooking struct {
+	qty     decimal.Decimal
+	cost    decimal.Decimal
+	fillQty decimal.Decimal
+	fills   map[string]bool
+}
+
+// NewLedger is a constructor for an empty Ledger using `method`.
+func NewLedger(method LotMethod) *Ledger {
+	return &Ledger{
+		Method:    method,
+		positions: make(map[int64]*LedgerPosition),
+		booked:    make(map[string]*orderBooking),
+	}
+}
+
+// Add books a fill.
+func (l *Ledger) Add(f Fill) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.add(f)
+}
+
+// add books a fill. The caller holds l.mu.
+func (l *Ledger) add(f Fill) error {
+	if f.TickerID == 0 {
+		return fmt.Errorf("fill is missing tickerId")
+	}
+	if !f.Quantity.IsPositive() || !f.Price.IsPositive() {
+		return fmt.Errorf("fill of %s at %s is invalid", f.Quantity, f.Price)
+	}
+	qty := f.Quantity
+	switch strings.ToUpper(string(f.Action)) {
+	case string(model.BUY):
+	case string(model.SELL):
+		qty = qty.Neg()
+	default:
+		return fmt.Errorf("fill has unknown action %q", f.Action)
+	}
+
+	pos, ok := l.positions[f.TickerID]
+	if !ok {
+		pos = &LedgerPosition{TickerID: f.TickerID}
+		l.positions[f.TickerID] = pos
+	}
+	if f.Symbol != "" {
+		pos.Symbol = f.Symbol
+	}
+	// commissions raise the price paid and lower the price received
+	perShare := f.Commission.Div(f.Quantity)
+	price := f.Price.Add(perShare)
+	if qty.IsNegative() {
+		price = f.Price.Sub(perShare)
+	}
+	pos.apply(l.Method, qty, price, f.Time)
+	return nil
+}
+
+// booking returns what was booked for order `id`. The caller holds l.mu.
+func (l *Ledger) booking(id string) *orderBooking {
+	b, ok := l.booked[id]
+	if !ok {
+		b = &orderBooking{fills: make(map[string]bool)}
+		l.booked[id] = b
+	}
+	return b
+}
+
+// addCumulative books the part of an order filled `qty` at average `avg` that
+// was not booked yet, at the price that brings the order's booked cost to
+// `qty` * `avg`. Without an average the fill is booked at `fallback`. The
+// order is only marked booked once the fill was accepted.
+func (l *Ledger) addCumulative(f Fill, qty, avg, fallback decimal.Decimal) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.booking(f.OrderID)
+	f.Quantity = qty.Sub(b.qty)
+	if !f.Quantity.IsPositive() {
+		return nil
+	}
+	f.Price = fallback
+	if avg.IsPositive() {
+		f.Price = qty.Mul(avg).Sub(b.cost).Div(f.Quantity)
+		if !f.Price.IsPositive() {
+			f.Price = avg
+		}
+	}
+	if err := l.add(f); err != nil {
+		return err
+	}
+	b.qty = qty
+	b.cost = b.cost.Add(f.Quantity.Mul(f.Price))
+	return nil
+}
+
+// AddOrder books the fills of a live or paper order not booked yet. Orders can
+// be added again as they fill further.
+func (l *Ledger) AddOrder(o *model.OrderItemV5) error {
+	if o == nil {
+		return nil
+	}
+	qty, avg, err := orderFillAmounts(o)
+	if err != nil || !qty.IsPositive() {
+		return err
+	}
+	f := Fill{OrderID: o.GetOrderId(), Action: model.OrderSide(o.GetAction())}
+	if len(o.Items) > 0 {
+		item := o.Items[0]
+		f.TickerID = item.GetTickerId()
+		f.Symbol = item.GetSymbol()
+		f.Time = time.UnixMilli(item.GetFilledTime0())
+		if f.Action == "" {
+			f.Action = model.OrderSide(item.GetAction())
+		}
+	}
+	return l.addCumulative(f, qty, avg, decimal.Zero)
+}
+
+// AddOrderFill books a fill returned by GetFilledOrdersByTicker, unless
+// AddOrder or AddOrderEvent already booked that quantity of its order.
+func (l *Ledger) AddOrderFill(fill *model.OrderFill) error {
+	qty, err := parseDecimal("filledQuantity", fill.FilledQuantity)
+	if err != nil {
+		return err
+	}
+	price, err := parseDecimal("filledPrice", fill.FilledPrice)
+	if err != nil {
+		return err
+	}
+	f := Fill{
+		OrderID:  fill.GetOrderId(),
+		TickerID: fill.GetTickerId(),
+		Symbol:   fill.Ticker.GetSymbol(),
+		Action:   model.OrderSide(fill.GetAction()),
+		Quantity: qty,
+		Price:    price,
+		Time:     time.UnixMilli(fill.GetFilledTime0()),
+	}
+	if f.TickerID == 0 {
+		f.TickerID = fill.Ticker.GetTickerId()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if f.OrderID == "" {
+		return l.add(f)
+	}
+	b := l.booking(f.OrderID)
+	key := fmt.Sprintf("%s/%d", fillKey(fill), fill.GetFilledTime0())
+	if b.fills[key] {
+		return nil
+	}
+	// the order's fills up to this one, of which b.qty is already booked
+	covered := b.fillQty.Add(qty)
+	if f.Quantity = covered.Sub(b.qty); f.Quantity.IsPositive() {
+		if err := l.add(f); err != nil {
+			return err
+		}
+		b.qty = covered
+		b.cost = b.cost.Add(f.Quantity.Mul(price))
+	}
+	b.fills[key] = true
+	b.fillQty = covered
+	return nil
+}
+
+// AddOrderEvent books the fill carried by an OrderTracker event. Like
+// AddOrder, only quantity not booked yet for the order is added, so an order
+// can be fed through both.
+func (l *Ledger) AddOrderEvent(e OrderEvent) error {
+	if e.FillQuantity <= 0 || e.Order == nil {
+		return nil
+	}
+	_, avg, err := orderFillAmounts(e.Order)
+	if err != nil {
+		return err
+	}
+	f := Fill{OrderID: e.OrderID, Action: model.OrderSide(e.Order.GetAction()), Time: e.Time}
+	if len(e.Order.Items) > 0 {
+		f.TickerID = e.Order.Items[0].GetTickerId()
+		f.Symbol = e.Order.Items[0].GetSymbol()
+	}
+	return l.addCumulative(f, decimal.NewFromFloat(e.FilledQuantity), avg, decimal.NewFromFloat(e.FillPrice))
+}
+
+// orderFillAmounts reads the filled quantity and average fill price of `o`,
+// deriving the price from the filled amount when no item carries it.
+func orderFillAmounts(o *model.OrderItemV5) (decimal.Decimal, decimal.Decimal, error) {
+	qty, err := parseOptionalDecimal("filledQuantity", o.FilledQuantity)
+	if err != nil {
+		return decimal.Zero, decimal.Zero, err
+	}
+	for _, item := range o.Items {
+		avg, err := parseOptionalDecimal("avgFilledPrice", item.AvgFilledPrice)
+		if err != nil {
+			return decimal.Zero, decimal.Zero, err
+		}
+		if avg.IsPositive() {
+			return qty, avg, nil
+		}
+	}
+	amount, err := parseOptionalDecimal("filledAmount", o.FilledAmount)
+	if err != nil || !qty.IsPositive() {
+		return qty, decimal.Zero, err
+	}
+	return qty, amount.Div(qty), nil
+}
+
+// AddSimFill books a PaperSimulator fill.
+func (l *Ledger) AddSimFill(f SimFill) error {
+	return l.Add(Fill{
+		OrderID:    f.OrderID,
+		TickerID:   f.TickerID,
+		Action:     f.Action,
+		Quantity:   decimal.NewFromFloat(f.Quantity),
+		Price:      decimal.NewFromFloat(f.Price),
+		Commission: decimal.NewFromFloat(f.Commission),
+		Time:       f.Time,
+	})
+}
+
+// SetPrice marks `tickerID` at `price`.
+func (l *Ledger) SetPrice(tickerID int64, price decimal.Decimal) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if pos, ok := l.positions[tickerID]; ok {
+		pos.LastPrice = price
+	}
+}
+
+// MarkToMarket prices every open position with real-time quotes from `quotes`.
+func (l *Ledger) MarkToMarket(quotes QuoteSource) error {
+	for _, pos := range l.Positions() {
+		if pos.Quantity.IsZero() {
+			continue
+		}
+		quote, err := quotes.GetRealtimeStockQuote(pos.TickerID)
+		if err != nil {
+			return err
+		}
+		price, err := parseDecimal("close", quote.Close)
+		if err != nil {
+			return fmt.Errorf("no price for ticker %d: %s", pos.TickerID, err.Error())
+		}
+		if !price.IsPositive() {
+			return fmt.Errorf("no price for ticker %d", pos.TickerID)
+		}
+		l.SetPrice(pos.TickerID, price)
+	}
+	return nil
+}
+
+// Positions lists every position seen, including closed ones holding realized
+// P&L, ordered by ticker ID.
+func (l *Ledger) Positions() []LedgerPosition {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	out := make([]LedgerPosition, 0, len(l.positions))
+	for _, p := range l.positions {
+		cp := *p
+		cp.Lots = append([]Lot(nil), p.Lots...)
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TickerID < out[j].TickerID })
+	return out
+}
+
+// Position returns the position in `tickerID`.
+func (l *Ledger) Position(tickerID int64) (LedgerPosition, bool) {
+	for _, p := range l.Positions() {
+		if p.TickerID == tickerID {
+			return p, true
+		}
+	}
+	return LedgerPosition{}, false
+}
+
+// RealizedPnL across all positions, net of commissions.
+func (l *Ledger) RealizedPnL() decimal.Decimal {
+	total := decimal.Zero
+	for _, p := range l.Positions() {
+		total = total.Add(p.RealizedPnL)
+	}
+	return total
+}
+
+// UnrealizedPnL across all priced positions.
+func (l *Ledger) UnrealizedPnL() decimal.Decimal {
+	total := decimal.Zero
+	for _, p := range l.Positions() {
+		total = total.Add(p.UnrealizedPnL())
+	}
+	return total
+}
+
+// PositionDiff is a mismatch between the ledger and the broker.
+type PositionDiff struct {
+	TickerID       int64
+	Symbol         string
+	LedgerQuantity decimal.Decimal
+	BrokerQuantity decimal.Decimal
+	LedgerAvgCost  decimal.Decimal
+	BrokerAvgCost  decimal.Decimal
+	Reason         string
+}
+
+// ReconcileReport compares ledger positions with broker-reported positions.
+type ReconcileReport struct {
+	Matched int
+	Diffs   []PositionDiff
+}
+
+// OK reports whether every position matched.
+func (r ReconcileReport) OK() bool {
+	return len(r.Diffs) == 0
+}
+
+// Reconcile compares open ledger positions with `broker` positions, such as
+// AccountSnapshot.Positions. Quantities must match exactly; average costs must
+// match within `costTolerance` per share, use a negative tolerance to skip them.
+func (l *Ledger) Reconcile(broker []PortfolioPosition, costTolerance decimal.Decimal) ReconcileReport {
+	var (
+		report = ReconcileReport{Diffs: make([]PositionDiff, 0)}
+		seen   = make(map[int64]bool)
+		ledger = make(map[int64]LedgerPosition)
+	)
+	for _, p := range l.Positions() {
+		if !p.Quantity.IsZero() {
+			ledger[p.TickerID] = p
+		}
+	}
+	for _, b := range broker {
+		seen[b.TickerID] = true
+		p, ok := ledger[b.TickerID]
+		diff := PositionDiff{
+			TickerID:       b.TickerID,
+			Symbol:         b.Symbol,
+			LedgerQuantity: p.Quantity,
+			BrokerQuantity: b.Quantity,
+			LedgerAvgCost:  p.AvgCost(),
+			BrokerAvgCost:  b.AvgCost,
+		}
+		switch {
+		case !ok && !b.Quantity.IsZero():
+			diff.Reason = "missing from ledger"
+		case !diff.LedgerQuantity.Equal(diff.BrokerQuantity):
+			diff.Reason = "quantity differs"
+		case !costTolerance.IsNegative() && diff.LedgerAvgCost.Sub(diff.BrokerAvgCost).Abs().GreaterThan(costTolerance):
+			diff.Reason = "cost basis differs"
+		default:
+			report.Matched++
+			continue
+		}
+		report.Diffs = append(report.Diffs, diff)
+	}
+	for id, p := range ledger {
+		if !seen[id] {
+			report.Diffs = append(report.Diffs, PositionDiff{
+				TickerID:       id,
+				Symbol:         p.Symbol,
+				LedgerQuantity: p.Quantity,
+				LedgerAvgCost:  p.AvgCost(),
+				Reason:         "missing at broker",
+			})
+		}
+	}
+	sort.Slice(report.Diffs, func(i, j int) bool { return report.Diffs[i].TickerID < report.Diffs[j].TickerID })
+	return report
+}
+
+// apply books a signed fill of `qty` at `price` against the position's lots.
+func (p *LedgerPosition) apply(method LotMethod, qty, price decimal.Decimal, at time.Time) {
+	remaining := qty
+	for !remaining.IsZero() && len(p.Lots) > 0 && p.Lots[0].Quantity.IsPositive() != remaining.IsPositive() {
+		i := 0
+		if method == LotLIFO {
+			i = len(p.Lots) - 1
+		}
+		lot := &p.Lots[i]
+		take := decimal.Min(remaining.Abs(), lot.Quantity.Abs())
+		if lot.Quantity.IsPositive() {
+			p.RealizedPnL = p.RealizedPnL.Add(take.Mul(price.Sub(lot.Price)))
+			lot.Quantity = lot.Quantity.Sub(take)
+			remaining = remaining.Add(take)
+		} else {
+			p.RealizedPnL = p.RealizedPnL.Add(take.Mul(lot.Price.Sub(price)))
+			lot.Quantity = lot.Quantity.Add(take)
+			remaining = remaining.Sub(take)
+		}
+		if lot.Quantity.IsZero() {
+			p.Lots = append(p.Lots[:i], p.Lots[i+1:]...)
+		}
+	}
+	if !remaining.IsZero() {
+		if method == LotAverageCost && len(p.Lots) > 0 {
+			lot := &p.Lots[0]
+			total := lot.Quantity.Add(remaining)
+			lot.Price = lot.Quantity.Mul(lot.Price).Add(remaining.Mul(price)).Div(total)
+			lot.Quantity = total
+		} else {
+			p.Lots = append(p.Lots, Lot{Quantity: remaining, Price: price, Time: at})
+		}
+	}
+	p.Quantity = decimal.Zero
+	for _, lot := range p.Lots {
+		p.Quantity = p.Quantity.Add(lot.Quantity)
+	}
+}
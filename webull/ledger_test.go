@@ -0,0 +1,152 @@
+package webull
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+var dec = decimal.RequireFromString
+
+func ledgerFills(l *Ledger) {
+	day := time.Date(2023, 1, 3, 15, 0, 0, 0, time.UTC)
+	_ = l.Add(Fill{TickerID: 1, Symbol: "AAPL", Action: model.BUY, Quantity: dec("10"), Price: dec("100"), Time: day})
+	_ = l.Add(Fill{TickerID: 1, Action: model.BUY, Quantity: dec("10"), Price: dec("120"), Time: day.AddDate(0, 0, 1)})
+	_ = l.Add(Fill{TickerID: 1, Action: model.SELL, Quantity: dec("15"), Price: dec("130"), Time: day.AddDate(0, 0, 2)})
+}
+
+func TestLedgerLotMethods(t *testing.T) {
+	asrt := assert.New(t)
+
+	fifo := NewLedger(LotFIFO)
+	ledgerFills(fifo)
+	pos, ok := fifo.Position(1)
+	asrt.True(ok)
+	asrt.Equal("AAPL", pos.Symbol)
+	asrt.Equal("5", pos.Quantity.String())
+	asrt.Equal("350", pos.RealizedPnL.String())
+	asrt.Equal("120", pos.AvgCost().String())
+
+	lifo := NewLedger(LotLIFO)
+	ledgerFills(lifo)
+	pos, _ = lifo.Position(1)
+	asrt.Equal("250", pos.RealizedPnL.String())
+	asrt.Equal("100", pos.AvgCost().String())
+
+	avg := NewLedger(LotAverageCost)
+	ledgerFills(avg)
+	pos, _ = avg.Position(1)
+	asrt.Equal("300", pos.RealizedPnL.String())
+	asrt.Equal("110", pos.AvgCost().String())
+	asrt.Len(pos.Lots, 1)
+
+	fifo.SetPrice(1, dec("125"))
+	asrt.Equal("25", fifo.UnrealizedPnL().String())
+	asrt.Equal("350", fifo.RealizedPnL().String())
+}
+
+func TestLedgerShortAndCommission(t *testing.T) {
+	asrt := assert.New(t)
+	l := NewLedger(LotFIFO)
+	asrt.Empty(l.Add(Fill{TickerID: 2, Action: model.SELL, Quantity: dec("10"), Price: dec("50"), Commission: dec("1")}))
+	asrt.Empty(l.Add(Fill{TickerID: 2, Action: model.BUY, Quantity: dec("15"), Price: dec("40"), Commission: dec("1.5")}))
+	pos, _ := l.Position(2)
+	asrt.Equal("5", pos.Quantity.String())
+	asrt.Equal("98", pos.RealizedPnL.String())
+	asrt.Equal("40.1", pos.AvgCost().String())
+
+	asrt.Error(l.Add(Fill{TickerID: 2, Action: "HOLD", Quantity: dec("1"), Price: dec("1")}))
+	asrt.Error(l.Add(Fill{Action: model.BUY, Quantity: dec("1"), Price: dec("1")}))
+}
+
+func TestLedgerAddOrderAndReconcile(t *testing.T) {
+	asrt := assert.New(t)
+	l := NewLedger(LotFIFO)
+
+	ord := model.OrderItemV5{
+		OrderId:        model.PtrString("9"),
+		Action:         model.PtrString("BUY"),
+		FilledQuantity: model.PtrString("4"),
+		Items:          []model.OrderItemV5ItemsInner{{TickerId: model.PtrInt64(3), Symbol: model.PtrString("SPY"), AvgFilledPrice: model.PtrString("400")}},
+	}
+	asrt.Empty(l.AddOrder(&ord))
+	// the same order read again after filling further only books the new shares
+	ord.FilledQuantity = model.PtrString("10")
+	ord.Items[0].AvgFilledPrice = model.PtrString("406")
+	asrt.Empty(l.AddOrder(&ord))
+	asrt.Empty(l.AddOrder(&ord))
+	// an event for fills AddOrder already booked adds nothing
+	asrt.Empty(l.AddOrderEvent(OrderEvent{OrderID: "9", FilledQuantity: 10, FillQuantity: 6, FillPrice: 410, Order: &ord}))
+	pos, _ := l.Position(3)
+	asrt.Equal("10", pos.Quantity.String())
+	asrt.Equal("406", pos.AvgCost().String())
+
+	s := NewPaperSimulator(10000)
+	s.UpdateQuote(1, SimQuote{Time: time.Date(2023, 3, 1, 15, 0, 0, 0, time.UTC), Last: 10})
+	s.UpdateQuote(3, SimQuote{Last: 410})
+	s.OnFill = func(f SimFill) { asrt.Empty(l.AddSimFill(f)) }
+	_, err := s.PlacePaperOrder(DefaultSimAccountID, newTestMarketOrder(model.BUY, 7, 1))
+	asrt.Empty(err)
+	asrt.Empty(l.MarkToMarket(s))
+	pos, _ = l.Position(1)
+	asrt.Equal("10", pos.LastPrice.String())
+
+	report := l.Reconcile([]PortfolioPosition{
+		{TickerID: 3, Symbol: "SPY", Quantity: decimal.NewFromInt(10), AvgCost: decimal.NewFromInt(406)},
+		{TickerID: 1, Quantity: decimal.NewFromInt(6), AvgCost: decimal.NewFromInt(10)},
+		{TickerID: 4, Symbol: "QQQ", Quantity: decimal.NewFromInt(1), AvgCost: decimal.NewFromInt(300)},
+	}, dec("0.01"))
+	asrt.False(report.OK())
+	asrt.Equal(1, report.Matched)
+	asrt.Len(report.Diffs, 2)
+	asrt.Equal("quantity differs", report.Diffs[0].Reason)
+	asrt.Equal("missing from ledger", report.Diffs[1].Reason)
+}
+
+func TestLedgerBooksOrderOnlyOnce(t *testing.T) {
+	asrt := assert.New(t)
+	l := NewLedger(LotFIFO)
+
+	// an order that fails to book is not marked booked
+	ord := model.OrderItemV5{
+		OrderId:        model.PtrString("7"),
+		Action:         model.PtrString("BUY"),
+		FilledQuantity: model.PtrString("5"),
+		Items:          []model.OrderItemV5ItemsInner{{AvgFilledPrice: model.PtrString("20")}},
+	}
+	asrt.Error(l.AddOrder(&ord))
+	ord.Items[0].TickerId = model.PtrInt64(5)
+	asrt.Empty(l.AddOrder(&ord))
+	pos, _ := l.Position(5)
+	asrt.Equal("5", pos.Quantity.String())
+
+	// its fills read back from GetFilledOrdersByTicker add nothing
+	fill := func(qty, price string, at int64) *model.OrderFill {
+		return &model.OrderFill{
+			OrderId: model.PtrString("7"), TickerId: model.PtrInt64(5), Action: model.PtrString("BUY"),
+			FilledQuantity: model.PtrString(qty), FilledPrice: model.PtrString(price), FilledTime0: model.PtrInt64(at),
+		}
+	}
+	asrt.Empty(l.AddOrderFill(fill("2", "19", 1)))
+	asrt.Empty(l.AddOrderFill(fill("3", "20.6667", 2)))
+	pos, _ = l.Position(5)
+	asrt.Equal("5", pos.Quantity.String())
+	asrt.Equal("20", pos.AvgCost().String())
+
+	// a fill past what AddOrder saw is booked once, and the order read again
+	// afterwards doesn't book it twice
+	asrt.Empty(l.AddOrderFill(fill("3", "22", 3)))
+	asrt.Empty(l.AddOrderFill(fill("3", "22", 3)))
+	ord.FilledQuantity = model.PtrString("8")
+	ord.Items[0].AvgFilledPrice = model.PtrString("20.75")
+	asrt.Empty(l.AddOrder(&ord))
+	pos, _ = l.Position(5)
+	asrt.Equal("8", pos.Quantity.String())
+	asrt.Equal("166", pos.CostBasis().String())
+
+	// fills are required to carry a quantity and price
+	asrt.Error(l.AddOrderFill(&model.OrderFill{OrderId: model.PtrString("8"), TickerId: model.PtrInt64(5), FilledQuantity: model.PtrString("1")}))
+}
@@ -0,0 +1,407 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RatingDistribution counts analyst ratings by recommendation.
+type RatingDistribution struct {
+	StrongBuy    int
+	Buy          int
+	Hold         int
+	UnderPerform int
+	Sell         int
+	Total        int
+	// Consensus is Webull's summary rating, e.g. "buy".
+	Consensus string
+}
+
+// Score is the mean rating from 1 for strong buy to 5 for sell, or 0 without
+// ratings.
+func (d RatingDistribution) Score() float64 {
+	n := d.StrongBuy + d.Buy + d.Hold + d.UnderPerform + d.Sell
+	if n == 0 {
+		return 0
+	}
+	return float64(d.StrongBuy+2*d.Buy+3*d.Hold+4*d.UnderPerform+5*d.Sell) / float64(n)
+}
+
+// PriceTarget is the analysts' price target range at a point in time.
+type PriceTarget struct {
+	Date time.Time
+	Low  float64
+	High float64
+	Mean float64
+	// Current is the share price when the target was taken.
+	Current float64
+}
+
+// Upside is the mean target above the current price, as a fraction of it.
+func (p PriceTarget) Upside() float64 {
+	if p.Current == 0 {
+		return 0
+	}
+	return p.Mean/p.Current - 1
+}
+
+// AnalystRatings summarises analyst coverage of a ticker.
+type AnalystRatings struct {
+	TickerID int64
+	Ratings  RatingDistribution
+	Target   PriceTarget
+}
+
+// NewsItem is a news headline.
+type NewsItem struct {
+	ID      int64
+	Title   string
+	Summary string
+	Source  string
+	URL     string
+	Time    time.Time
+}
+
+// GetAnalystRatings gets the analyst rating distribution and price target of
+// ticker `tickerID` from its stock analysis, the endpoint of GetStockAnalysis.
+// Both are zero for a ticker without analyst coverage.
+func (c *Client) GetAnalystRatings(tickerID int64) (*AnalystRatings, error) {
+	var response stockAnalysisResponse
+	err := c.getStockAnalysis(strconv.FormatInt(tickerID, 10), &response)
+	if err != nil {
+		return nil, err
+	}
+	return newAnalystRatings(tickerID, response)
+}
+
+// GetPriceTargetHistory gets the price targets of ticker `tickerID` over time,
+// oldest first.
+func (c *Client) GetPriceTargetHistory(tickerID int64) ([]PriceTarget, error) {
+	var (
+		u, _        = url.Parse(StockInfoEndpoint + "/securities/ticker/v5/analysis/" + strconv.FormatInt(tickerID, 10) + "/targetPrice")
+		response    priceTargetsResponse
+		headersMap  = make(map[string]string)
+		queryParams = make(map[string]string)
+	)
+
+	headersMap[HeaderKeyAccessToken] = c.AccessToken
+	headersMap[HeaderKeyDeviceID] = c.DeviceID
+
+	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
+	if err != nil {
+		return nil, err
+	}
+	return newPriceTargets(response)
+}
+
+// GetNewsPager walks the news of ticker `tickerID`, newest first, using the
+// `currentNewsId` cursor.
+func (c *Client) GetNewsPager(tickerID int64, pageSize int) *Pager[NewsItem] {
+	return NewPager(func(ctx context.Context, cursor string) ([]NewsItem, string, error) {
+		if cursor == "" {
+			cursor = "0"
+		}
+		items, err := c.getNewsPage(tickerID, cursor, pageSize)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(items) < pageSize {
+			return items, "", nil
+		}
+		return items, strconv.FormatInt(items[len(items)-1].ID, 10), nil
+	})
+}
+
+// GetNewsSince gets the news of ticker `tickerID` published after `since`,
+// newest first.
+func (c *Client) GetNewsSince(ctx context.Context, tickerID int64, since time.Time) ([]NewsItem, error) {
+	return newsSince(ctx, c.GetNewsPager(tickerID, newsPageSize), since, 0)
+}
+
+func (c *Client) getNewsPage(tickerID int64, currentNewsID string, count int) ([]NewsItem, error) {
+	var (
+		u, _        = url.Parse(StockInfoEndpoint + "/information/news/tickerNews")
+		response    []newsItemResponse
+		headersMap  = make(map[string]string)
+		queryParams = make(map[string]string)
+	)
+
+	headersMap[HeaderKeyAccessToken] = c.AccessToken
+	headersMap[HeaderKeyDeviceID] = c.DeviceID
+
+	queryParams["tickerId"] = strconv.FormatInt(tickerID, 10)
+	queryParams["currentNewsId"] = currentNewsID
+	queryParams["pageSize"] = strconv.Itoa(count)
+
+	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
+	if err != nil {
+		return nil, err
+	}
+	return newNewsItems(response)
+}
+
+// newsPageSize is the page size used when polling news.
+const newsPageSize = 20
+
+// NewsPoller polls the news of several tickers, returning each headline once.
+type NewsPoller struct {
+	client    *Client
+	tickerIDs []int64
+
+	mu sync.Mutex
+	// since and lastID are the newest headline seen per ticker
+	since  map[int64]time.Time
+	lastID map[int64]int64
+}
+
+// NewNewsPoller is a constructor for a NewsPoller of `tickerIDs` reporting
+// headlines published after `since`.
+func NewNewsPoller(c *Client, since time.Time, tickerIDs ...int64) *NewsPoller {
+	p := &NewsPoller{
+		client:    c,
+		tickerIDs: tickerIDs,
+		since:     make(map[int64]time.Time),
+		lastID:    make(map[int64]int64),
+	}
+	for _, id := range tickerIDs {
+		p.since[id] = since
+	}
+	return p
+}
+
+// NewsPollError reports the tickers a poll failed for. Headlines of the other
+// tickers are still returned.
+type NewsPollError struct {
+	Errors map[int64]error
+}
+
+func (e *NewsPollError) Error() string {
+	ids := make([]int64, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		msgs = append(msgs, fmt.Sprintf("ticker %d: %v", id, e.Errors[id]))
+	}
+	return "news poll failed for " + strings.Join(msgs, "; ")
+}
+
+// Poll returns the headlines published since the previous poll, keyed by
+// ticker ID, newest first. A failing ticker does not stop the others; its
+// error is reported in a *NewsPollError and it is retried on the next poll.
+func (p *NewsPoller) Poll(ctx context.Context) (map[int64][]NewsItem, error) {
+	return p.poll(ctx, func(tickerID int64) *Pager[NewsItem] {
+		return p.client.GetNewsPager(tickerID, newsPageSize)
+	})
+}
+
+// Run polls every `interval` until `ctx` is done, passing new headlines to
+// `callback`. Polling errors are passed to `callback` too and polling goes on.
+func (p *NewsPoller) Run(ctx context.Context, interval time.Duration, callback func(map[int64][]NewsItem, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		news, err := p.Poll(ctx)
+		if err != nil || len(news) > 0 {
+			callback(news, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *NewsPoller) poll(ctx context.Context, pager func(tickerID int64) *Pager[NewsItem]) (map[int64][]NewsItem, error) {
+	news := make(map[int64][]NewsItem)
+	failed := make(map[int64]error)
+	for _, tickerID := range p.tickerIDs {
+		p.mu.Lock()
+		since, lastID := p.since[tickerID], p.lastID[tickerID]
+		p.mu.Unlock()
+
+		items, err := newsSince(ctx, pager(tickerID), since, lastID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return news, ctx.Err()
+			}
+			failed[tickerID] = err
+			continue
+		}
+		if len(items) == 0 {
+			continue
+		}
+		news[tickerID] = items
+		p.mu.Lock()
+		if items[0].Time.After(since) {
+			p.since[tickerID] = items[0].Time
+		}
+		p.lastID[tickerID] = items[0].ID
+		p.mu.Unlock()
+	}
+	if len(failed) > 0 {
+		return news, &NewsPollError{Errors: failed}
+	}
+	return news, nil
+}
+
+// newsSince reads `pager` until headlines are no newer than `since` or are
+// headline `lastID`.
+func newsSince(ctx context.Context, pager *Pager[NewsItem], since time.Time, lastID int64) ([]NewsItem, error) {
+	items := make([]NewsItem, 0)
+	for {
+		item, err := pager.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return items, err
+		}
+		if (lastID != 0 && item.ID == lastID) || !item.Time.After(since) {
+			break
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Time.After(items[j].Time) })
+	return items, nil
+}
+
+// newsItemResponse is a headline of the ticker news endpoint.
+type newsItemResponse struct {
+	ID         ID        `json:"id"`
+	Title      string    `json:"title"`
+	Summary    string    `json:"summary"`
+	SourceName string    `json:"sourceName"`
+	NewsURL    string    `json:"newsUrl"`
+	NewsTime   Timestamp `json:"newsTime"`
+}
+
+// newNewsItems reads headlines, which must carry their ID, the paging cursor,
+// and publication time.
+func newNewsItems(response []newsItemResponse) ([]NewsItem, error) {
+	items := make([]NewsItem, 0, len(response))
+	for _, n := range response {
+		item := NewsItem{
+			ID:      n.ID.Int64(),
+			Title:   n.Title,
+			Summary: n.Summary,
+			Source:  n.SourceName,
+			URL:     n.NewsURL,
+			Time:    n.NewsTime.Time,
+		}
+		switch {
+		case item.ID == 0:
+			return nil, fmt.Errorf("news item %q is missing id", n.Title)
+		case item.Time.IsZero():
+			return nil, fmt.Errorf("news item %d is missing newsTime", item.ID)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// stockAnalysisResponse is the part of the stock analysis body holding
+// analyst ratings and the price target.
+type stockAnalysisResponse struct {
+	Rating *struct {
+		RatingAnalysis       string  `json:"ratingAnalysis"`
+		RatingAnalysisTotals *Number `json:"ratingAnalysisTotals"`
+		RatingSpread         *struct {
+			StrongBuy    Number `json:"strongBuy"`
+			Buy          Number `json:"buy"`
+			Hold         Number `json:"hold"`
+			UnderPerform Number `json:"underPerform"`
+			Sell         Number `json:"sell"`
+		} `json:"ratingSpread"`
+	} `json:"rating"`
+	TargetPrice *priceTargetResponse `json:"targetPrice"`
+}
+
+// priceTargetResponse is a price target range.
+type priceTargetResponse struct {
+	Date    Timestamp `json:"date"`
+	Low     *Number   `json:"low"`
+	High    *Number   `json:"high"`
+	Mean    *Number   `json:"mean"`
+	Current *Number   `json:"current"`
+}
+
+// priceTargetsResponse is the body of the price target history endpoint.
+type priceTargetsResponse struct {
+	Data []priceTargetResponse `json:"data"`
+}
+
+// newAnalystRatings reads the ratings and price target of a stock analysis.
+// Either may be left out, but ratings must then carry their spread and the
+// target its mean.
+func newAnalystRatings(tickerID int64, response stockAnalysisResponse) (*AnalystRatings, error) {
+	r := &AnalystRatings{TickerID: tickerID}
+	if rating := response.Rating; rating != nil {
+		spread := rating.RatingSpread
+		if spread == nil {
+			return nil, fmt.Errorf("analyst rating of ticker %d is missing ratingSpread", tickerID)
+		}
+		r.Ratings = RatingDistribution{
+			StrongBuy:    int(spread.StrongBuy),
+			Buy:          int(spread.Buy),
+			Hold:         int(spread.Hold),
+			UnderPerform: int(spread.UnderPerform),
+			Sell:         int(spread.Sell),
+			Total:        int(optionalNumber(rating.RatingAnalysisTotals)),
+			Consensus:    rating.RatingAnalysis,
+		}
+		if rating.RatingAnalysisTotals == nil {
+			d := r.Ratings
+			r.Ratings.Total = d.StrongBuy + d.Buy + d.Hold + d.UnderPerform + d.Sell
+		}
+	}
+	if response.TargetPrice != nil {
+		target, err := newPriceTarget(*response.TargetPrice)
+		if err != nil {
+			return nil, err
+		}
+		r.Target = target
+	}
+	return r, nil
+}
+
+func newPriceTarget(t priceTargetResponse) (PriceTarget, error) {
+	if t.Mean == nil {
+		return PriceTarget{}, fmt.Errorf("price target is missing mean")
+	}
+	return PriceTarget{
+		Date:    t.Date.Time,
+		Low:     optionalNumber(t.Low),
+		High:    optionalNumber(t.High),
+		Mean:    t.Mean.Float64(),
+		Current: optionalNumber(t.Current),
+	}, nil
+}
+
+// newPriceTargets reads a price target history, oldest first. Each target must
+// be dated.
+func newPriceTargets(response priceTargetsResponse) ([]PriceTarget, error) {
+	targets := make([]PriceTarget, 0, len(response.Data))
+	for _, t := range response.Data {
+		if t.Date.IsZero() {
+			return nil, fmt.Errorf("price target is missing date")
+		}
+		target, err := newPriceTarget(t)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	sort.SliceStable(targets, func(i, j int) bool { return targets[i].Date.Before(targets[j].Date) })
+	return targets, nil
+}
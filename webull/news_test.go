@@ -0,0 +1,183 @@
+package webull
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+// The analysis and news bodies are hand-written, not captured.
+func TestAnalystRatings(t *testing.T) {
+	asrt := assert.New(t)
+	var response stockAnalysisResponse
+	asrt.Empty(json.Unmarshal([]byte(`{
+		"rating":{"ratingAnalysis":"buy","ratingAnalysisTotals":10,
+			"ratingSpread":{"strongBuy":4,"buy":3,"hold":2,"underPerform":0,"sell":1}},
+		"targetPrice":{"low":"150.00","high":"220.00","mean":"190.50","current":"173.75"}}`), &response))
+	ratings, err := newAnalystRatings(913256135, response)
+	asrt.Empty(err)
+	asrt.Equal(int64(913256135), ratings.TickerID)
+	asrt.Equal(RatingDistribution{StrongBuy: 4, Buy: 3, Hold: 2, Sell: 1, Total: 10, Consensus: "buy"}, ratings.Ratings)
+	asrt.InDelta(2.1, ratings.Ratings.Score(), 1e-9)
+	asrt.Equal(190.5, ratings.Target.Mean)
+	asrt.Equal(150.0, ratings.Target.Low)
+	asrt.InDelta(190.5/173.75-1, ratings.Target.Upside(), 1e-9)
+	asrt.Equal(0.0, RatingDistribution{}.Score())
+
+	uncovered, err := newAnalystRatings(1, stockAnalysisResponse{})
+	asrt.Empty(err)
+	asrt.Equal(RatingDistribution{}, uncovered.Ratings)
+	for _, body := range []string{`{"rating":{"ratingAnalysis":"buy"}}`, `{"targetPrice":{"avg":"190.50"}}`} {
+		var response stockAnalysisResponse
+		asrt.Empty(json.Unmarshal([]byte(body), &response))
+		_, err := newAnalystRatings(1, response)
+		asrt.Error(err, body)
+	}
+
+	var history priceTargetsResponse
+	asrt.Empty(json.Unmarshal([]byte(`{"data":[{"date":"2023-03-01","mean":"185"},{"date":"2023-01-01","mean":"170"}]}`), &history))
+	targets, err := newPriceTargets(history)
+	asrt.Empty(err)
+	asrt.Len(targets, 2)
+	asrt.Equal(170.0, targets[0].Mean)
+	asrt.Equal(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), targets[1].Date)
+	var undated priceTargetsResponse
+	asrt.Empty(json.Unmarshal([]byte(`{"data":[{"mean":"185"}]}`), &undated))
+	_, err = newPriceTargets(undated)
+	asrt.Error(err)
+}
+
+func TestNewsList(t *testing.T) {
+	asrt := assert.New(t)
+	var response []newsItemResponse
+	asrt.Empty(json.Unmarshal([]byte(`[{"id":4321,"title":"Apple unveils","summary":"New devices",
+		"sourceName":"Reuters","newsUrl":"https://example.com/a","newsTime":"2023-03-01T12:00:00.000+0000"}]`), &response))
+	news, err := newNewsItems(response)
+	asrt.Empty(err)
+	asrt.Len(news, 1)
+	asrt.Equal(NewsItem{
+		ID:      4321,
+		Title:   "Apple unveils",
+		Summary: "New devices",
+		Source:  "Reuters",
+		URL:     "https://example.com/a",
+		Time:    time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC),
+	}, NewsItem{news[0].ID, news[0].Title, news[0].Summary, news[0].Source, news[0].URL, news[0].Time.UTC()})
+
+	for _, body := range []string{`[{"newsId":1,"newsTime":"2023-03-01"}]`, `[{"id":1,"publishTime":"2023-03-01"}]`} {
+		var response []newsItemResponse
+		asrt.Empty(json.Unmarshal([]byte(body), &response))
+		_, err := newNewsItems(response)
+		asrt.Error(err, body)
+	}
+}
+
+// newsServer serves `ids` as headlines, newest first, published an hour apart.
+// Requests for ticker `failing` get a server error.
+type newsServer struct {
+	ids      []int64
+	failing  string
+	requests []*http.Request
+}
+
+func newsTime(id int64) time.Time {
+	return time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(id) * time.Hour)
+}
+
+func (t *newsServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	t.requests = append(t.requests, req)
+	if t.failing != "" && req.URL.Query().Get("tickerId") == t.failing {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = io.WriteString(w, `{"msg":"unavailable","code":"500"}`)
+		return
+	}
+	// the first page has no cursor
+	cursor, _ := strconv.ParseInt(req.URL.Query().Get("currentNewsId"), 10, 64)
+	size, _ := strconv.Atoi(req.URL.Query().Get("pageSize"))
+	items := make([]string, 0)
+	for _, id := range t.ids {
+		if (cursor == 0 || id < cursor) && len(items) < size {
+			items = append(items, `{"id":`+strconv.FormatInt(id, 10)+`,"title":"headline","newsTime":"`+
+				newsTime(id).Format("2006-01-02T15:04:05.000-0700")+`"}`)
+		}
+	}
+	_, _ = io.WriteString(w, `[`+strings.Join(items, ",")+`]`)
+}
+
+func TestGetNewsPager(t *testing.T) {
+	asrt := assert.New(t)
+	server := &newsServer{ids: []int64{50, 40, 30, 20, 10}}
+	c := newTestClient(t, server)
+	news, err := c.GetNewsPager(913256135, 2).All(context.Background())
+	asrt.Empty(err)
+	asrt.Len(news, 5)
+	asrt.Len(server.requests, 3)
+	asrt.Equal("0", server.requests[0].URL.Query().Get("currentNewsId"))
+	asrt.Equal("40", server.requests[1].URL.Query().Get("currentNewsId"))
+	asrt.Equal("913256135", server.requests[0].URL.Query().Get("tickerId"))
+
+	news, err = c.GetNewsSince(context.Background(), 913256135, newsTime(30))
+	asrt.Empty(err)
+	asrt.Len(news, 2)
+	asrt.Equal(int64(50), news[0].ID)
+}
+
+func TestNewsPoller(t *testing.T) {
+	asrt := assert.New(t)
+	server := &newsServer{ids: []int64{30, 20, 10}}
+	c := newTestClient(t, server)
+	poller := NewNewsPoller(c, newsTime(15), 1)
+	news, err := poller.Poll(context.Background())
+	asrt.Empty(err)
+	asrt.Len(news[1], 2)
+
+	news, err = poller.Poll(context.Background())
+	asrt.Empty(err)
+	asrt.Empty(news)
+
+	server.ids = append([]int64{40}, server.ids...)
+	news, err = poller.Poll(context.Background())
+	asrt.Empty(err)
+	asrt.Len(news[1], 1)
+	asrt.Equal(int64(40), news[1][0].ID)
+
+	server.failing = "1"
+	poller = NewNewsPoller(c, newsTime(15), 1, 2)
+	news, err = poller.Poll(context.Background())
+	asrt.Error(err)
+	pollErr, ok := err.(*NewsPollError)
+	asrt.True(ok)
+	asrt.Len(pollErr.Errors, 1)
+	asrt.Error(pollErr.Errors[1])
+	asrt.Len(news[2], 3, "ticker 2 is polled after ticker 1 fails")
+}
+
+func TestGetAnalystRatings(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	ratings, err := c.GetAnalystRatings(913256135)
+	asrt.Empty(err)
+	asrt.NotNil(ratings)
+	news, err := c.GetNewsSince(context.Background(), 913256135, time.Now().AddDate(0, 0, -7))
+	asrt.Empty(err)
+	asrt.NotNil(news)
+}
@@ -0,0 +1,544 @@
+package webull
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"quantfu.com/webull/client/pricing"
+)
+
+// optionExpiryLayout is the expiry date format sent to the option list endpoint.
+const optionExpiryLayout = "01/02/2006"
+
+// OptionDirection is either OptionCall or OptionPut.
+type OptionDirection string
+
+// Option directions
+const (
+	OptionCall OptionDirection = "call"
+	OptionPut  OptionDirection = "put"
+)
+
+// Moneyness is a contract's strike relative to the underlying price.
+type Moneyness string
+
+// Moneyness values
+const (
+	InTheMoney    Moneyness = "ITM"
+	AtTheMoney    Moneyness = "ATM"
+	OutOfTheMoney Moneyness = "OTM"
+	// UnknownMoneyness is reported without an underlying price.
+	UnknownMoneyness Moneyness = "unknown"
+)
+
+// DefaultATMBand is the distance from the underlying price, as a fraction of
+// it, within which a strike counts as at the money.
+const DefaultATMBand = 0.01
+
+// OptionContract is a single option with its latest quote and greeks.
+type OptionContract struct {
+	// ID is the contract's derivative ID, used to quote and trade it.
+	ID         int64
+	Symbol     string
+	Underlying string
+	Direction  OptionDirection
+	Strike     float64
+	Expiry     time.Time
+	Bid        float64
+	BidSize    float64
+	Ask        float64
+	AskSize    float64
+	Last       float64
+	Volume     float64
+	// OpenInterest is the number of contracts open as of the previous close.
+	OpenInterest float64
+	Multiplier   float64
+	// IV is the implied volatility as a fraction, e.g. 0.25 for 25%.
+	IV    float64
+	Delta float64
+	Gamma float64
+	Theta float64
+	Vega  float64
+	Rho   float64
+}
+
+// Mid is the midpoint of the bid and ask, or the last price without both.
+func (o OptionContract) Mid() float64 {
+	if o.Bid > 0 && o.Ask > 0 {
+		return (o.Bid + o.Ask) / 2
+	}
+	return o.Last
+}
+
+// Moneyness classifies the contract against `underlying`, treating strikes
+// within `atmBand` of it as at the money. It is UnknownMoneyness when
+// `underlying` is not positive.
+func (o OptionContract) Moneyness(underlying, atmBand float64) Moneyness {
+	if underlying <= 0 {
+		return UnknownMoneyness
+	}
+	if math.Abs(o.Strike-underlying) <= atmBand*underlying {
+		return AtTheMoney
+	}
+	if (o.Direction == OptionCall) == (o.Strike < underlying) {
+		return InTheMoney
+	}
+	return OutOfTheMoney
+}
+
+// OptionPair is the call and put sharing a strike and expiry. Either may be nil.
+type OptionPair struct {
+	Expiry time.Time
+	Strike float64
+	Call   *OptionContract
+	Put    *OptionContract
+}
+
+// OptionFilter selects contracts from an OptionChain. Zero fields select everything.
+type OptionFilter struct {
+	Expiry    time.Time
+	MinStrike float64
+	MaxStrike float64
+	Direction OptionDirection
+	Moneyness Moneyness
+	// ATMBand overrides DefaultATMBand when filtering by Moneyness.
+	ATMBand float64
+}
+
+// OptionChain is the option contracts listed on an underlying.
+type OptionChain struct {
+	TickerID        int64
+	UnderlyingPrice float64
+	Contracts       []OptionContract
+
+	client *Client
+	mu     sync.RWMutex
+}
+
+// GetOptionChain gets the option chain of ticker `tickerID` for `expiry`, or
+// for every expiry when it is zero. The underlying price is the ticker's
+// real-time quote, and is left zero when that can't be fetched or parsed.
+func (c *Client) GetOptionChain(tickerID int64, expiry time.Time) (*OptionChain, error) {
+	var (
+		response   optionListResponse
+		expireDate string
+		queryAll   int32 = 1
+	)
+	if !expiry.IsZero() {
+		expireDate = expiry.Format(optionExpiryLayout)
+		queryAll = 0
+	}
+	err := c.getStockOptions(strconv.FormatInt(tickerID, 10), expireDate, "all", -1, 1, queryAll, &response)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := newOptionChain(tickerID, response)
+	if err != nil {
+		return nil, err
+	}
+	chain.client = c
+	if quote, err := c.GetRealtimeStockQuote(tickerID); err == nil {
+		chain.UnderlyingPrice, _ = parseOptionalFloat("close", quote.Close)
+	}
+	return chain, nil
+}
+
+// Expirations lists the distinct expiry dates in the chain, soonest first.
+func (ch *OptionChain) Expirations() []time.Time {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	seen := make(map[time.Time]bool)
+	expiries := make([]time.Time, 0)
+	for _, o := range ch.Contracts {
+		if !seen[o.Expiry] {
+			seen[o.Expiry] = true
+			expiries = append(expiries, o.Expiry)
+		}
+	}
+	sort.Slice(expiries, func(i, j int) bool { return expiries[i].Before(expiries[j]) })
+	return expiries
+}
+
+// Strikes lists the distinct strikes in the chain, lowest first.
+func (ch *OptionChain) Strikes() []float64 {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	seen := make(map[float64]bool)
+	strikes := make([]float64, 0)
+	for _, o := range ch.Contracts {
+		if !seen[o.Strike] {
+			seen[o.Strike] = true
+			strikes = append(strikes, o.Strike)
+		}
+	}
+	sort.Float64s(strikes)
+	return strikes
+}
+
+// Filter returns a chain holding only the contracts matching `f`. The returned
+// chain shares the client, so it can be refreshed on its own. Filtering by
+// Moneyness fails when the chain has no underlying price.
+func (ch *OptionChain) Filter(f OptionFilter) (*OptionChain, error) {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	if f.Moneyness != "" && ch.UnderlyingPrice <= 0 {
+		return nil, fmt.Errorf("option chain of ticker %d has no underlying price to filter by moneyness", ch.TickerID)
+	}
+	band := f.ATMBand
+	if band <= 0 {
+		band = DefaultATMBand
+	}
+	out := &OptionChain{TickerID: ch.TickerID, UnderlyingPrice: ch.UnderlyingPrice, client: ch.client}
+	for _, o := range ch.Contracts {
+		switch {
+		case !f.Expiry.IsZero() && !sameDay(o.Expiry, f.Expiry):
+		case f.MinStrike > 0 && o.Strike < f.MinStrike:
+		case f.MaxStrike > 0 && o.Strike > f.MaxStrike:
+		case f.Direction != "" && o.Direction != f.Direction:
+		case f.Moneyness != "" && o.Moneyness(ch.UnderlyingPrice, band) != f.Moneyness:
+		default:
+			out.Contracts = append(out.Contracts, o)
+		}
+	}
+	return out, nil
+}
+
+// Pairs pairs calls and puts by expiry and strike, ordered by expiry then strike.
+func (ch *OptionChain) Pairs() []OptionPair {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	type key struct {
+		expiry time.Time
+		strike float64
+	}
+	byKey := make(map[key]*OptionPair)
+	for i := range ch.Contracts {
+		o := ch.Contracts[i]
+		k := key{o.Expiry, o.Strike}
+		pair, ok := byKey[k]
+		if !ok {
+			pair = &OptionPair{Expiry: o.Expiry, Strike: o.Strike}
+			byKey[k] = pair
+		}
+		if o.Direction == OptionPut {
+			pair.Put = &o
+		} else {
+			pair.Call = &o
+		}
+	}
+	pairs := make([]OptionPair, 0, len(byKey))
+	for _, p := range byKey {
+		pairs = append(pairs, *p)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if !pairs[i].Expiry.Equal(pairs[j].Expiry) {
+			return pairs[i].Expiry.Before(pairs[j].Expiry)
+		}
+		return pairs[i].Strike < pairs[j].Strike
+	})
+	return pairs
+}
+
+// PricingInputs describes the contract for the pricing package, with the
+// underlying at `spot` and volatility set to the contract's IV.
+func (o OptionContract) PricingInputs(spot float64, now time.Time, rate, dividendYield float64) pricing.Inputs {
+	return pricing.Inputs{
+		Call:          o.Direction == OptionCall,
+		Spot:          spot,
+		Strike:        o.Strike,
+		Years:         pricing.YearsToExpiry(now, o.Expiry),
+		Rate:          rate,
+		DividendYield: dividendYield,
+		Volatility:    o.IV,
+	}
+}
+
+// FillGreeks computes the implied volatility and greeks Webull left out, using
+// the American approximation from the mid price and the chain's underlying
+// price. It returns the number of contracts it filled in, which is none when
+// the chain has no underlying price.
+func (ch *OptionChain) FillGreeks(now time.Time, rate, dividendYield float64) int {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	filled := 0
+	if ch.UnderlyingPrice <= 0 {
+		return filled
+	}
+	for i := range ch.Contracts {
+		o := &ch.Contracts[i]
+		if o.IV != 0 && o.Delta != 0 && o.Gamma != 0 {
+			continue
+		}
+		in := o.PricingInputs(ch.UnderlyingPrice, now, rate, dividendYield)
+		if in.Volatility == 0 {
+			iv, err := pricing.AmericanImpliedVolatility(o.Mid(), in)
+			if err != nil {
+				continue
+			}
+			in.Volatility = iv
+		}
+		g := pricing.American(in)
+		o.IV = in.Volatility
+		o.Delta, o.Gamma, o.Theta, o.Vega, o.Rho = g.Delta, g.Gamma, g.Theta, g.Vega, g.Rho
+		filled++
+	}
+	return filled
+}
+
+// Contract returns the contract with derivative ID `id`.
+func (ch *OptionChain) Contract(id int64) (OptionContract, bool) {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	for _, o := range ch.Contracts {
+		if o.ID == id {
+			return o, true
+		}
+	}
+	return OptionContract{}, false
+}
+
+// Refresh updates the quotes and greeks of the contracts with `ids`, or of
+// every contract in the chain when none are given, via GetOptionsQuotes.
+func (ch *OptionChain) Refresh(ids ...int64) error {
+	if ch.client == nil {
+		return fmt.Errorf("option chain has no client to refresh with")
+	}
+	if len(ids) == 0 {
+		ch.mu.RLock()
+		for _, o := range ch.Contracts {
+			ids = append(ids, o.ID)
+		}
+		ch.mu.RUnlock()
+	}
+	for start := 0; start < len(ids); start += quotesBatchSize {
+		end := start + quotesBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		derivativeIDs := make([]string, 0, end-start)
+		for _, id := range ids[start:end] {
+			derivativeIDs = append(derivativeIDs, strconv.FormatInt(id, 10))
+		}
+		var response optionQuotesResponse
+		err := ch.client.getOptionsQuotes(strconv.FormatInt(ch.TickerID, 10), strings.Join(derivativeIDs, ","), &response)
+		if err != nil {
+			return err
+		}
+		if err := ch.update(response.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// optionListResponse is the body of the option list endpoint, which lists
+// contracts in `data` or, for several expiries, grouped by expiry in
+// `expireDateList`.
+type optionListResponse struct {
+	ExpireDateList []optionExpiryGroup `json:"expireDateList"`
+	Data           []optionListEntry   `json:"data"`
+}
+
+// optionExpiryGroup is the contracts of one expiry.
+type optionExpiryGroup struct {
+	From struct {
+		Date Timestamp `json:"date"`
+	} `json:"from"`
+	Data []optionListEntry `json:"data"`
+}
+
+// optionListEntry is either a contract or a strike with its call and put.
+type optionListEntry struct {
+	optionQuote
+	Call *optionQuote `json:"call"`
+	Put  *optionQuote `json:"put"`
+}
+
+// optionQuotesResponse is the body of the option quotes endpoint.
+type optionQuotesResponse struct {
+	Data []optionQuote `json:"data"`
+}
+
+// optionQuote is a contract as the option endpoints and the quote stream send
+// it. Values are pointers, so a quote leaving one out keeps the known value.
+type optionQuote struct {
+	DerivativeID    ID          `json:"derivativeId"`
+	Symbol          *string     `json:"symbol"`
+	UnSymbol        *string     `json:"unSymbol"`
+	Direction       *string     `json:"direction"`
+	StrikePrice     *Number     `json:"strikePrice"`
+	ExpireDate      *Timestamp  `json:"expireDate"`
+	Close           *Number     `json:"close"`
+	Volume          *Number     `json:"volume"`
+	OpenInterest    *Number     `json:"openInterest"`
+	QuoteMultiplier *Number     `json:"quoteMultiplier"`
+	ImpVol          *Number     `json:"impVol"`
+	Delta           *Number     `json:"delta"`
+	Gamma           *Number     `json:"gamma"`
+	Theta           *Number     `json:"theta"`
+	Vega            *Number     `json:"vega"`
+	Rho             *Number     `json:"rho"`
+	BidList         []bookLevel `json:"bidList"`
+	AskList         []bookLevel `json:"askList"`
+}
+
+// bookLevel is a price level of a bidList or askList.
+type bookLevel struct {
+	Price  Number `json:"price"`
+	Volume Number `json:"volume"`
+}
+
+// update merges refreshed `quotes` into the matching contracts, keeping values
+// the refresh did not carry.
+func (ch *OptionChain) update(quotes []optionQuote) error {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	for _, q := range quotes {
+		id := q.DerivativeID.Int64()
+		if id == 0 {
+			return fmt.Errorf("option quote for ticker %d is missing derivativeId", ch.TickerID)
+		}
+		for i := range ch.Contracts {
+			if ch.Contracts[i].ID == id {
+				ch.Contracts[i].apply(q)
+			}
+		}
+	}
+	return nil
+}
+
+// newOptionChain reads an option list response. Every contract must carry its
+// derivative ID, direction, strike and expiry, taking the direction and strike
+// from an enclosing strike and the expiry from an enclosing expiry group.
+func newOptionChain(tickerID int64, response optionListResponse) (*OptionChain, error) {
+	ch := &OptionChain{TickerID: tickerID}
+	groups := append([]optionExpiryGroup{{Data: response.Data}}, response.ExpireDateList...)
+	seen := make(map[int64]bool)
+	for _, group := range groups {
+		for _, entry := range group.Data {
+			quotes := []optionQuote{entry.optionQuote}
+			if entry.Call != nil || entry.Put != nil {
+				quotes = quotes[:0]
+				for _, side := range []struct {
+					direction string
+					quote     *optionQuote
+				}{{string(OptionCall), entry.Call}, {string(OptionPut), entry.Put}} {
+					if side.quote == nil {
+						continue
+					}
+					q := *side.quote
+					if q.Direction == nil {
+						direction := side.direction
+						q.Direction = &direction
+					}
+					if q.StrikePrice == nil {
+						q.StrikePrice = entry.StrikePrice
+					}
+					quotes = append(quotes, q)
+				}
+			}
+			for _, q := range quotes {
+				if q.ExpireDate == nil && !group.From.Date.IsZero() {
+					q.ExpireDate = &group.From.Date
+				}
+				o, err := newOptionContract(q)
+				if err != nil {
+					return nil, err
+				}
+				if !seen[o.ID] {
+					seen[o.ID] = true
+					ch.Contracts = append(ch.Contracts, o)
+				}
+			}
+		}
+	}
+	sort.SliceStable(ch.Contracts, func(i, j int) bool {
+		a, b := ch.Contracts[i], ch.Contracts[j]
+		if !a.Expiry.Equal(b.Expiry) {
+			return a.Expiry.Before(b.Expiry)
+		}
+		if a.Strike != b.Strike {
+			return a.Strike < b.Strike
+		}
+		if a.Direction != b.Direction {
+			return a.Direction < b.Direction
+		}
+		return a.ID < b.ID
+	})
+	return ch, nil
+}
+
+// newOptionContract reads a listed contract, failing when it lacks a value
+// every contract has.
+func newOptionContract(q optionQuote) (OptionContract, error) {
+	var o OptionContract
+	switch {
+	case q.DerivativeID.Int64() == 0:
+		return o, fmt.Errorf("option contract is missing derivativeId")
+	case q.Direction == nil:
+		return o, fmt.Errorf("option contract %s is missing direction", q.DerivativeID)
+	case q.StrikePrice == nil:
+		return o, fmt.Errorf("option contract %s is missing strikePrice", q.DerivativeID)
+	case q.ExpireDate == nil || q.ExpireDate.IsZero():
+		return o, fmt.Errorf("option contract %s is missing expireDate", q.DerivativeID)
+	}
+	switch strings.ToLower(*q.Direction) {
+	case string(OptionCall), string(OptionPut):
+	default:
+		return o, fmt.Errorf("option contract %s has unknown direction %q", q.DerivativeID, *q.Direction)
+	}
+	o.apply(q)
+	return o, nil
+}
+
+// apply sets the contract values `q` carries.
+func (o *OptionContract) apply(q optionQuote) {
+	setFloat := func(dst *float64, v *Number) {
+		if v != nil {
+			*dst = v.Float64()
+		}
+	}
+	if id := q.DerivativeID.Int64(); id != 0 {
+		o.ID = id
+	}
+	if q.Symbol != nil {
+		o.Symbol = *q.Symbol
+	}
+	if q.UnSymbol != nil {
+		o.Underlying = *q.UnSymbol
+	}
+	if q.Direction != nil {
+		o.Direction = OptionDirection(strings.ToLower(*q.Direction))
+	}
+	if q.ExpireDate != nil && !q.ExpireDate.IsZero() {
+		o.Expiry = q.ExpireDate.Time
+	}
+	setFloat(&o.Strike, q.StrikePrice)
+	setFloat(&o.Last, q.Close)
+	setFloat(&o.Volume, q.Volume)
+	setFloat(&o.OpenInterest, q.OpenInterest)
+	setFloat(&o.Multiplier, q.QuoteMultiplier)
+	setFloat(&o.IV, q.ImpVol)
+	setFloat(&o.Delta, q.Delta)
+	setFloat(&o.Gamma, q.Gamma)
+	setFloat(&o.Theta, q.Theta)
+	setFloat(&o.Vega, q.Vega)
+	setFloat(&o.Rho, q.Rho)
+	if len(q.BidList) > 0 {
+		o.Bid, o.BidSize = q.BidList[0].Price.Float64(), q.BidList[0].Volume.Float64()
+	}
+	if len(q.AskList) > 0 {
+		o.Ask, o.AskSize = q.AskList[0].Price.Float64(), q.AskList[0].Volume.Float64()
+	}
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
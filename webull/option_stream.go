@@ -0,0 +1,165 @@
+package webull
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// DefaultOptionMessageTypes are the quote message types subscribed for option
+// contracts: quotes with greeks, trades and the top of the book.
+var DefaultOptionMessageTypes = []string{"102", "103", "104"}
+
+// OptionQuoteEvent is a streamed quote for an option contract. Values not
+// carried by the message are zero; OptionChain.Apply only updates the values
+// the message carried.
+type OptionQuoteEvent struct {
+	Topic        Topic
+	DerivativeID int64
+	Time         time.Time
+	Bid          float64
+	BidSize      float64
+	Ask          float64
+	AskSize      float64
+	Last         float64
+	Volume       float64
+	OpenInterest float64
+	IV           float64
+	Delta        float64
+	Gamma        float64
+	Theta        float64
+	Vega         float64
+	Rho          float64
+
+	quote optionQuote
+}
+
+// ConnectStreamingOptionQuotes streams quotes for the option contracts with
+// `derivativeIDs`, as listed by GetStockOptions or OptionChain.DerivativeIDs.
+// Callbacks registered for `messageTypes` receive an OptionQuoteEvent for
+// these contracts; DefaultOptionMessageTypes are used when none are given.
+// While ConnectStreamingQuotes is streaming, the contracts are subscribed on
+// its connection and this returns at once; otherwise it opens the connection
+// and blocks like ConnectStreamingQuotes.
+func (c *Client) ConnectStreamingOptionQuotes(ctx context.Context, messageTypes []string, derivativeIDs []int64) error {
+	if len(messageTypes) == 0 {
+		messageTypes = DefaultOptionMessageTypes
+	}
+	ids := make([]string, 0, len(derivativeIDs))
+	c.streamMu.Lock()
+	if c.streamingOptions == nil {
+		c.streamingOptions = make(map[int64]bool)
+	}
+	for _, id := range derivativeIDs {
+		c.streamingOptions[id] = true
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	conn := c.quoteStream
+	c.streamMu.Unlock()
+	if conn != nil {
+		return subscribeQuoteTopics(conn, messageTypes, ids)
+	}
+	return c.ConnectStreamingQuotes(ctx, c.Username, c.HashedPassword, c.DeviceID, c.session().access, messageTypes, ids)
+}
+
+// RegisterOptionCallback registers `callback` for option quote events on
+// `topic` message types, see RegisterCallback. Messages for stocks are ignored.
+func (c *Client) RegisterOptionCallback(override bool, callback func(context.Context, OptionQuoteEvent) error, topic ...string) error {
+	return c.RegisterCallback(override, func(ctx context.Context, t Topic, message interface{}) error {
+		if event, ok := message.(OptionQuoteEvent); ok {
+			return callback(ctx, event)
+		}
+		return nil
+	}, topic...)
+}
+
+// DerivativeIDs lists the derivative IDs of every contract in the chain, to
+// stream with ConnectStreamingOptionQuotes.
+func (ch *OptionChain) DerivativeIDs() []int64 {
+	ch.mu.RLock()
+	defer ch.mu.RUnlock()
+	ids := make([]int64, 0, len(ch.Contracts))
+	for _, o := range ch.Contracts {
+		ids = append(ids, o.ID)
+	}
+	return ids
+}
+
+// Apply updates the chain with a streamed quote.
+func (ch *OptionChain) Apply(event OptionQuoteEvent) {
+	q := event.quote
+	q.DerivativeID = ID(strconv.FormatInt(event.DerivativeID, 10))
+	_ = ch.update([]optionQuote{q})
+}
+
+// streamMessage converts messages for streamed option contracts into an
+// OptionQuoteEvent, passing other messages through, as well as those that
+// don't decode as an option quote.
+func (c *Client) streamMessage(topic Topic, message interface{}) interface{} {
+	c.streamMu.Lock()
+	option := c.streamingOptions[int64(topic.TickerID)]
+	c.streamMu.Unlock()
+	if !option {
+		return message
+	}
+	event, err := newOptionQuoteEvent(topic, message)
+	if err != nil {
+		return message
+	}
+	return event
+}
+
+// optionStreamMessage is an option quote, trade or book message.
+type optionStreamMessage struct {
+	optionQuote
+	TickerID   ID     `json:"tickerId"`
+	TradeStamp Number `json:"tradeStamp"`
+	Deal       *struct {
+		Price *Number `json:"price"`
+	} `json:"deal"`
+}
+
+// newOptionQuoteEvent reads an option quote from a stream message, which
+// pushChannel decodes into a map.
+func newOptionQuoteEvent(topic Topic, message interface{}) (OptionQuoteEvent, error) {
+	var m optionStreamMessage
+	b, err := json.Marshal(message)
+	if err != nil {
+		return OptionQuoteEvent{}, err
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return OptionQuoteEvent{}, err
+	}
+	// a trade carries its price in the deal and only its own size, which is
+	// not the day's volume
+	if m.Deal != nil && m.Close == nil {
+		m.Close = m.Deal.Price
+	}
+
+	var o OptionContract
+	o.apply(m.optionQuote)
+	e := OptionQuoteEvent{
+		Topic:        topic,
+		DerivativeID: int64(topic.TickerID),
+		Time:         stampTime(int64(m.TradeStamp)),
+		Bid:          o.Bid,
+		BidSize:      o.BidSize,
+		Ask:          o.Ask,
+		AskSize:      o.AskSize,
+		Last:         o.Last,
+		Volume:       o.Volume,
+		OpenInterest: o.OpenInterest,
+		IV:           o.IV,
+		Delta:        o.Delta,
+		Gamma:        o.Gamma,
+		Theta:        o.Theta,
+		Vega:         o.Vega,
+		Rho:          o.Rho,
+		quote:        m.optionQuote,
+	}
+	if e.DerivativeID == 0 {
+		e.DerivativeID = m.TickerID.Int64()
+	}
+	return e, nil
+}
@@ -0,0 +1,109 @@
+package webull
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamOptionQuotes(t *testing.T) {
+	asrt := assert.New(t)
+	c := &Client{streamingOptions: map[int64]bool{1002: true}}
+
+	stock := map[string]interface{}{"tickerId": 913256135, "close": "150.20"}
+	asrt.Equal(stock, c.streamMessage(Topic{Type: 102, TickerID: 913256135}, stock))
+
+	// messages arrive from the websocket decoded into maps
+	message := c.streamMessage(Topic{Type: 102, TickerID: 1002}, map[string]interface{}{
+		"tickerId":   1002,
+		"tradeStamp": 1678993200000,
+		"close":      "0.42",
+		"volume":     "1250",
+		"impVol":     "0.3120",
+		"delta":      "-0.2210",
+		"gamma":      "0.0410",
+	})
+	event, ok := message.(OptionQuoteEvent)
+	asrt.True(ok)
+	asrt.Equal(int64(1002), event.DerivativeID)
+	asrt.Equal(time.UnixMilli(1678993200000), event.Time)
+	asrt.Equal(0.42, event.Last)
+	asrt.Equal(1250.0, event.Volume)
+	asrt.Equal(0.312, event.IV)
+	asrt.Equal(-0.221, event.Delta)
+
+	book, err := newOptionQuoteEvent(Topic{Type: 104, TickerID: 1002}, map[string]interface{}{
+		"tickerId": 1002,
+		"bidList":  []interface{}{map[string]interface{}{"price": "0.40", "volume": "7"}},
+		"askList":  []interface{}{map[string]interface{}{"price": "0.44", "volume": "3"}},
+	})
+	asrt.Empty(err)
+	asrt.Equal(0.4, book.Bid)
+	asrt.Equal(7.0, book.BidSize)
+	asrt.Equal(0.44, book.Ask)
+
+	trade, err := newOptionQuoteEvent(Topic{Type: 103, TickerID: 1002}, map[string]interface{}{
+		"tickerId": 1002,
+		"deal":     map[string]interface{}{"price": "0.43", "volume": "2"},
+	})
+	asrt.Empty(err)
+	asrt.Equal(0.43, trade.Last)
+	asrt.Equal(0.0, trade.Volume, "a trade's size is not the day's volume")
+
+	ch := testOptionChain(t)
+	ch.Apply(event)
+	ch.Apply(book)
+	o, _ := ch.Contract(1002)
+	asrt.Equal(0.42, o.Last)
+	asrt.Equal(-0.221, o.Delta)
+	asrt.Equal(0.4, o.Bid)
+	asrt.Equal(0.44, o.Ask)
+	asrt.Equal(3100.0, o.OpenInterest, "kept when not streamed")
+	asrt.Equal(145.0, o.Strike)
+	asrt.Contains(ch.DerivativeIDs(), int64(1002))
+
+	bad := map[string]interface{}{"tickerId": 1002, "close": "n/a"}
+	asrt.Equal(bad, c.streamMessage(Topic{Type: 102, TickerID: 1002}, bad), "passed through when not an option quote")
+}
+
+func TestRegisterOptionCallback(t *testing.T) {
+	asrt := assert.New(t)
+	c := &Client{streamingOptions: map[int64]bool{1002: true}}
+	var events []OptionQuoteEvent
+	asrt.Empty(c.RegisterOptionCallback(false, func(ctx context.Context, e OptionQuoteEvent) error {
+		events = append(events, e)
+		return nil
+	}, DefaultOptionMessageTypes...))
+
+	callback := c.WebsocketCallbacks["102"]
+	asrt.NotNil(callback)
+	asrt.Empty(callback(context.Background(), Topic{Type: 102, TickerID: 913256135}, c.streamMessage(Topic{Type: 102, TickerID: 913256135}, map[string]interface{}{"close": "1"})))
+	asrt.Empty(callback(context.Background(), Topic{Type: 102, TickerID: 1002}, c.streamMessage(Topic{Type: 102, TickerID: 1002}, map[string]interface{}{"close": "1"})))
+	asrt.Len(events, 1)
+	asrt.Equal(int64(1002), events[0].DerivativeID)
+}
+
+// fakeQuoteStream records subscriptions made on an open quote stream.
+type fakeQuoteStream struct {
+	MQTT.Client
+	topics []string
+}
+
+func (f *fakeQuoteStream) Subscribe(topic string, qos byte, callback MQTT.MessageHandler) MQTT.Token {
+	f.topics = append(f.topics, topic)
+	return &MQTT.DummyToken{}
+}
+
+func TestConnectStreamingOptionQuotesSharesConnection(t *testing.T) {
+	asrt := assert.New(t)
+	stream := &fakeQuoteStream{}
+	c := &Client{}
+	c.setQuoteStream(stream)
+	asrt.Empty(c.ConnectStreamingOptionQuotes(context.Background(), []string{"102"}, []int64{1002, 1003}))
+	asrt.Equal([]string{`{"tickerIds": [1002,1003],"type": "102"}`}, stream.topics)
+	_, ok := c.streamMessage(Topic{Type: 102, TickerID: 1003}, map[string]interface{}{}).(OptionQuoteEvent)
+	asrt.True(ok)
+}
@@ -9,9 +9,15 @@ import (
 
 // GetStockOptions queries for options quotes.
 func (c *Client) GetStockOptions(tickerID, expireDate, direction string, count, includeWeekly, queryAll int32) (*model.GetStockOptionsResponse, error) {
+	var response model.GetStockOptionsResponse
+	err := c.getStockOptions(tickerID, expireDate, direction, count, includeWeekly, queryAll, &response)
+	return &response, err
+}
+
+// getStockOptions is GetStockOptions decoding into `response`.
+func (c *Client) getStockOptions(tickerID, expireDate, direction string, count, includeWeekly, queryAll int32, response interface{}) error {
 	var (
 		u, _        = url.Parse(BrokerQuotesEndpoint + "/quote/option/" + tickerID + "/list")
-		response    model.GetStockOptionsResponse
 		headersMap  = make(map[string]string)
 		queryParams = make(map[string]string)
 	)
@@ -29,19 +35,20 @@ func (c *Client) GetStockOptions(tickerID, expireDate, direction string, count,
 	queryParams["expireDate"] = expireDate
 	queryParams["queryAll"] = fmt.Sprintf("%d", queryAll)
 
-	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
-	if err != nil {
-		return &response, err
-	}
-
-	return &response, err
+	return c.GetAndDecode(*u, response, &headersMap, &queryParams)
 }
 
 // GetOptionsQuotes gets options quotes.
 func (c *Client) GetOptionsQuotes(tickerID, derivativeIds string) (*model.GetStockOptionsResponse, error) {
+	var response model.GetStockOptionsResponse
+	err := c.getOptionsQuotes(tickerID, derivativeIds, &response)
+	return &response, err
+}
+
+// getOptionsQuotes is GetOptionsQuotes decoding into `response`.
+func (c *Client) getOptionsQuotes(tickerID, derivativeIds string, response interface{}) error {
 	var (
 		u, _        = url.Parse(BrokerQuotesGWEndpoint + "/quote/option/query/list")
-		response    model.GetStockOptionsResponse
 		headersMap  = make(map[string]string)
 		queryParams = make(map[string]string)
 	)
@@ -52,10 +59,5 @@ func (c *Client) GetOptionsQuotes(tickerID, derivativeIds string) (*model.GetSto
 	queryParams[QueryKeyTickerID] = tickerID
 	queryParams["derivativeIds"] = derivativeIds
 
-	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
-	if err != nil {
-		return &response, err
-	}
-
-	return &response, err
+	return c.GetAndDecode(*u, response, &headersMap, &queryParams)
 }
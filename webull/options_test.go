@@ -1,10 +1,13 @@
 package webull
 
 import (
+	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"quantfu.com/webull/client/pricing"
 	model "quantfu.com/webull/openapi"
 )
 
@@ -45,3 +48,167 @@ func TestGetOptionsQuotes(t *testing.T) {
 	asrt.NotEmpty(acc)
 	asrt.Empty(err)
 }
+
+// optionListFixture is hand-written, not captured, in the two shapes the option
+// list endpoint is known to use.
+const optionListFixture = `{"tickerId":913256135,"expireDateList":[
+	{"from":{"date":"2023-03-17","days":3},"data":[
+		{"derivativeId":1001,"symbol":"AAPL230317C00145000","unSymbol":"AAPL","direction":"call","strikePrice":"145","close":"5.60",
+			"bidList":[{"price":"5.50","volume":"12"}],"askList":[{"price":"5.70","volume":"8"}],
+			"openInterest":"1520","impVol":"0.2810","delta":"0.81","gamma":"0.04","theta":"-0.12","vega":"0.05","rho":"0.01","quoteMultiplier":"100"},
+		{"derivativeId":1002,"unSymbol":"AAPL","direction":"put","strikePrice":"145","close":"0.35","openInterest":"3100","impVol":"0.30"},
+		{"derivativeId":1003,"unSymbol":"AAPL","direction":"call","strikePrice":"150","close":"2.10"},
+		{"derivativeId":1004,"unSymbol":"AAPL","direction":"put","strikePrice":"150","close":"1.90"},
+		{"derivativeId":1005,"unSymbol":"AAPL","direction":"call","strikePrice":"160","close":"0.15"}]},
+	{"from":{"date":"2023-04-21","days":38},"data":[
+		{"strikePrice":"150","call":{"derivativeId":2001,"close":"6.10"},"put":{"derivativeId":2002,"close":"5.40"}}]}]}`
+
+func testOptionChain(t *testing.T) *OptionChain {
+	var response optionListResponse
+	if err := json.Unmarshal([]byte(optionListFixture), &response); err != nil {
+		t.Fatal(err)
+	}
+	ch, err := newOptionChain(913256135, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch.UnderlyingPrice = 150.2
+	return ch
+}
+
+func TestOptionChain(t *testing.T) {
+	asrt := assert.New(t)
+	ch := testOptionChain(t)
+	asrt.Equal(150.2, ch.UnderlyingPrice)
+	asrt.Len(ch.Contracts, 7)
+
+	march := time.Date(2023, 3, 17, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2023, 4, 21, 0, 0, 0, 0, time.UTC)
+	asrt.Equal([]time.Time{march, april}, ch.Expirations())
+	asrt.Equal([]float64{145, 150, 160}, ch.Strikes())
+
+	o, ok := ch.Contract(1001)
+	asrt.True(ok)
+	asrt.Equal(OptionCall, o.Direction)
+	asrt.Equal(march, o.Expiry)
+	asrt.Equal(5.5, o.Bid)
+	asrt.Equal(8.0, o.AskSize)
+	asrt.InDelta(5.6, o.Mid(), 1e-9)
+	asrt.Equal(0.281, o.IV)
+	asrt.Equal(-0.12, o.Theta)
+	asrt.Equal(1520.0, o.OpenInterest)
+	asrt.Equal(InTheMoney, o.Moneyness(ch.UnderlyingPrice, DefaultATMBand))
+
+	o, _ = ch.Contract(2002)
+	asrt.Equal(OptionPut, o.Direction)
+	asrt.Equal(150.0, o.Strike)
+	asrt.Equal(april, o.Expiry)
+
+	filtered, err := ch.Filter(OptionFilter{Expiry: march, Direction: OptionCall})
+	asrt.Empty(err)
+	asrt.Len(filtered.Contracts, 3)
+	strikes, err := ch.Filter(OptionFilter{MinStrike: 146, MaxStrike: 155})
+	asrt.Empty(err)
+	asrt.Len(strikes.Contracts, 4)
+	atm, err := ch.Filter(OptionFilter{Moneyness: AtTheMoney})
+	asrt.Empty(err)
+	asrt.Len(atm.Contracts, 4)
+	otm, err := ch.Filter(OptionFilter{Expiry: march, Moneyness: OutOfTheMoney})
+	asrt.Empty(err)
+	asrt.Len(otm.Contracts, 2)
+
+	unpriced := &OptionChain{TickerID: ch.TickerID, Contracts: ch.Contracts}
+	asrt.Equal(UnknownMoneyness, o.Moneyness(0, DefaultATMBand))
+	_, err = unpriced.Filter(OptionFilter{Moneyness: AtTheMoney})
+	asrt.Error(err)
+	_, err = unpriced.Filter(OptionFilter{Direction: OptionPut})
+	asrt.Empty(err)
+
+	pairs := ch.Pairs()
+	asrt.Len(pairs, 4)
+	asrt.Equal(145.0, pairs[0].Strike)
+	asrt.Equal(int64(1001), pairs[0].Call.ID)
+	asrt.Equal(int64(1002), pairs[0].Put.ID)
+	asrt.Nil(pairs[2].Put)
+	asrt.Equal(april, pairs[3].Expiry)
+
+	var quotes optionQuotesResponse
+	asrt.Empty(json.Unmarshal([]byte(`{"data":[{"derivativeId":"1002","bidList":[{"price":"0.30","volume":"4"}],"delta":"-0.19"}]}`), &quotes))
+	asrt.Empty(ch.update(quotes.Data))
+	o, _ = ch.Contract(1002)
+	asrt.Equal(0.3, o.Bid)
+	asrt.Equal(-0.19, o.Delta)
+	asrt.Equal(3100.0, o.OpenInterest, "kept across refreshes")
+	asrt.Error(ch.update([]optionQuote{{}}), "quotes must name their contract")
+
+	asrt.Error(ch.Refresh())
+}
+
+func TestOptionChainRequiredFields(t *testing.T) {
+	asrt := assert.New(t)
+	for _, body := range []string{
+		`{"data":[{"direction":"call","strikePrice":"145","expireDate":"2023-03-17"}]}`,
+		`{"data":[{"derivativeId":1,"direction":"call","strikePrice":"145"}]}`,
+		`{"data":[{"derivativeId":1,"strikePrice":"145","expireDate":"2023-03-17"}]}`,
+		`{"data":[{"derivativeId":1,"direction":"both","strikePrice":"145","expireDate":"2023-03-17"}]}`,
+		`{"expireDateList":[{"from":{"date":"2023-03-17"},"data":[{"call":{"derivativeId":1}}]}]}`,
+	} {
+		var response optionListResponse
+		asrt.Empty(json.Unmarshal([]byte(body), &response))
+		_, err := newOptionChain(1, response)
+		asrt.Error(err, body)
+	}
+
+	var response optionListResponse
+	asrt.Error(json.Unmarshal([]byte(`{"data":[{"derivativeId":1,"strikePrice":"n/a"}]}`), &response))
+}
+
+func TestOptionChainFillGreeks(t *testing.T) {
+	asrt := assert.New(t)
+	ch := testOptionChain(t)
+	now := time.Date(2023, 3, 1, 15, 0, 0, 0, time.UTC)
+	before, _ := ch.Contract(1001)
+
+	filled := ch.FillGreeks(now, 0.045, 0.006)
+	asrt.Equal(6, filled, "every contract but the one quoted with greeks")
+	after, _ := ch.Contract(1001)
+	asrt.Equal(before, after)
+
+	put, _ := ch.Contract(1002)
+	asrt.Equal(0.3, put.IV, "quoted IV is kept")
+	asrt.True(put.Delta < 0 && put.Delta > -0.5)
+	call, _ := ch.Contract(1003)
+	asrt.True(call.IV > 0)
+	asrt.True(call.Delta > 0.3 && call.Delta < 0.7)
+	asrt.True(call.Theta < 0)
+
+	in := call.PricingInputs(ch.UnderlyingPrice, now, 0.045, 0.006)
+	asrt.InDelta(2.1, pricing.American(in).Price, 1e-4)
+
+	unpriced := testOptionChain(t)
+	unpriced.UnderlyingPrice = 0
+	asrt.Equal(0, unpriced.FillGreeks(now, 0.045, 0.006))
+	call, _ = unpriced.Contract(1003)
+	asrt.Equal(0.0, call.IV)
+}
+
+func TestGetOptionChain(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	ch, err := c.GetOptionChain(913256135, time.Time{})
+	asrt.Empty(err)
+	asrt.NotEmpty(ch.Expirations())
+	near, err := ch.Filter(OptionFilter{Expiry: ch.Expirations()[0], Moneyness: AtTheMoney})
+	asrt.Empty(err)
+	asrt.Empty(near.Refresh())
+}
@@ -0,0 +1,366 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	model "quantfu.com/webull/openapi"
+)
+
+const (
+	// DefaultTrackerPollInterval is how often an OrderTracker refreshes orders
+	// when no push notification arrives.
+	DefaultTrackerPollInterval = time.Second * 2
+	// DefaultTrackerPageSize is how many orders an OrderTracker fetches per page.
+	DefaultTrackerPageSize = 100
+)
+
+// OrderEvent is emitted by an OrderTracker when a tracked order changes.
+type OrderEvent struct {
+	OrderID string
+	// ReplacedBy is set when the order was cancelled as part of a cancel-replace.
+	ReplacedBy string
+	Status     string
+	// FilledQuantity is the cumulative filled quantity.
+	FilledQuantity float64
+	// FillQuantity and FillPrice describe the fill since the previous event, if any.
+	FillQuantity float64
+	FillPrice    float64
+	Terminal     bool
+	Order        *model.OrderItemV5
+	Time         time.Time
+}
+
+// OrderTracker follows submitted orders until they reach a terminal state.
+// Run must be running for waits to make progress.
+type OrderTracker struct {
+	PollInterval time.Duration
+	PageSize     int32
+	// OnEvent is called for every status change and partial fill.
+	OnEvent func(OrderEvent)
+	// OnError is called with every failed poll while Run keeps going. When it
+	// is nil Run returns the first error instead.
+	OnError func(error)
+
+	client    *Client
+	accountID int64
+	paper     bool
+
+	mu       sync.Mutex
+	orders   map[string]*trackedOrder
+	replaced map[string]string
+	wake     chan struct{}
+}
+
+type trackedOrder struct {
+	since     time.Time
+	order     *model.OrderItemV5
+	status    string
+	filledQty float64
+	avgPrice  float64
+	done      chan struct{}
+}
+
+// NewOrderTracker is a constructor for tracking live (V5) orders on account `accountID`.
+func NewOrderTracker(c *Client, accountID int64) *OrderTracker {
+	return &OrderTracker{
+		PollInterval: DefaultTrackerPollInterval,
+		PageSize:     DefaultTrackerPageSize,
+		client:       c,
+		accountID:    accountID,
+		orders:       make(map[string]*trackedOrder),
+		replaced:     make(map[string]string),
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// NewPaperOrderTracker is a constructor for tracking paper orders on account `paperAccountID`.
+func NewPaperOrderTracker(c *Client, paperAccountID int64) *OrderTracker {
+	t := NewOrderTracker(c, paperAccountID)
+	t.paper = true
+	return t
+}
+
+// Track starts following order `orderID`.
+func (t *OrderTracker) Track(orderID string) {
+	t.mu.Lock()
+	t.track(orderID)
+	t.mu.Unlock()
+	t.Notify()
+}
+
+func (t *OrderTracker) track(orderID string) *trackedOrder {
+	if o, ok := t.orders[orderID]; ok {
+		return o
+	}
+	o := &trackedOrder{since: time.Now(), done: make(chan struct{})}
+	t.orders[orderID] = o
+	return o
+}
+
+// Replace records that `oldID` was cancelled and replaced by `newID`. Waiters on
+// `oldID` follow the chain and resolve with the replacement order.
+func (t *OrderTracker) Replace(oldID, newID string) {
+	t.mu.Lock()
+	t.replaced[oldID] = newID
+	t.track(oldID)
+	t.track(newID)
+	t.mu.Unlock()
+	t.Notify()
+}
+
+// Notify asks the tracker to refresh immediately, e.g. from an order push callback.
+func (t *OrderTracker) Notify() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run polls tracked orders until `ctx` is done or a poll fails without an
+// OnError handler.
+func (t *OrderTracker) Run(ctx context.Context) error {
+	interval := t.PollInterval
+	if interval <= 0 {
+		interval = DefaultTrackerPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if t.pending() {
+			if err := t.poll(ctx); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if t.OnError == nil {
+					return err
+				}
+				t.OnError(err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-t.wake:
+		}
+	}
+}
+
+func (t *OrderTracker) pending() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, o := range t.orders {
+		if !isTerminalStatus(o.status) {
+			return true
+		}
+	}
+	return false
+}
+
+// Poll pages through orders, newest first, until every pending tracked order
+// has been refreshed or no orders are left.
+func (t *OrderTracker) Poll() error {
+	return t.poll(context.Background())
+}
+
+func (t *OrderTracker) poll(ctx context.Context) error {
+	wanted, since := t.wanted()
+	if len(wanted) == 0 {
+		return nil
+	}
+	pageSize := t.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultTrackerPageSize
+	}
+	// orders are listed by creation day, so start a day before the first was tracked
+	since = since.AddDate(0, 0, -1)
+	var pager *Pager[*model.OrderItemV5]
+	if t.paper {
+		pager = t.client.GetPaperOrdersPager(t.accountID, model.ALL, since, pageSize)
+	} else {
+		pager = t.client.GetOrdersV5Pager(t.accountID, model.ALL, since, pageSize)
+	}
+	for len(wanted) > 0 {
+		o, err := pager.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err = t.update(o); err != nil {
+			return err
+		}
+		if o != nil && o.OrderId != nil {
+			delete(wanted, *o.OrderId)
+		}
+	}
+	return nil
+}
+
+// wanted returns the pending tracked orders and when the earliest of them was
+// first tracked.
+func (t *OrderTracker) wanted() (map[string]bool, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	wanted := make(map[string]bool)
+	var since time.Time
+	for id, o := range t.orders {
+		if isTerminalStatus(o.status) {
+			continue
+		}
+		wanted[id] = true
+		if since.IsZero() || o.since.Before(since) {
+			since = o.since
+		}
+	}
+	return wanted, since
+}
+
+// update applies a fresh snapshot of an order and emits events for any change.
+func (t *OrderTracker) update(o *model.OrderItemV5) error {
+	if o == nil || o.OrderId == nil {
+		return nil
+	}
+	filledQty, err := parseOptionalFloat("filledQuantity", o.FilledQuantity)
+	if err != nil {
+		return fmt.Errorf("order %s: %s", *o.OrderId, err.Error())
+	}
+	avg, err := orderAvgFilledPrice(o)
+	if err != nil {
+		return fmt.Errorf("order %s: %s", *o.OrderId, err.Error())
+	}
+	t.mu.Lock()
+	tr, ok := t.orders[*o.OrderId]
+	if !ok || isTerminalStatus(tr.status) {
+		t.mu.Unlock()
+		return nil
+	}
+	ev := OrderEvent{
+		OrderID:        *o.OrderId,
+		ReplacedBy:     t.replaced[*o.OrderId],
+		Status:         o.GetStatus(),
+		FilledQuantity: filledQty,
+		Order:          o,
+		Time:           time.Now(),
+	}
+	ev.FillQuantity, ev.FillPrice = fillDelta(tr.filledQty, tr.avgPrice, ev.FilledQuantity, avg)
+	changed := ev.FillQuantity > 0 || !strings.EqualFold(tr.status, ev.Status)
+
+	tr.order = o
+	tr.status = ev.Status
+	tr.filledQty = ev.FilledQuantity
+	tr.avgPrice = avg
+	if isTerminalStatus(ev.Status) {
+		ev.Terminal = true
+		close(tr.done)
+	}
+	onEvent := t.OnEvent
+	t.mu.Unlock()
+
+	if changed && onEvent != nil {
+		onEvent(ev)
+	}
+	return nil
+}
+
+// WaitTerminal blocks until order `orderID`, or the last order in its
+// cancel-replace chain, reaches a terminal state.
+func (t *OrderTracker) WaitTerminal(ctx context.Context, orderID string) (*model.OrderItemV5, error) {
+	id := orderID
+	for {
+		t.mu.Lock()
+		tr := t.track(id)
+		t.mu.Unlock()
+		t.Notify()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-tr.done:
+		}
+
+		t.mu.Lock()
+		next, ok := t.replaced[id]
+		order := tr.order
+		t.mu.Unlock()
+		if !ok {
+			return order, nil
+		}
+		id = next
+	}
+}
+
+// WaitFilled blocks until order `orderID` is filled. An error is returned if
+// it reaches any other terminal state.
+func (t *OrderTracker) WaitFilled(ctx context.Context, orderID string) (*model.OrderItemV5, error) {
+	order, err := t.WaitTerminal(ctx, orderID)
+	if err != nil {
+		return order, err
+	}
+	if !strings.EqualFold(order.GetStatus(), string(model.FILLED)) {
+		return order, fmt.Errorf("order %s ended with status %s", order.GetOrderId(), order.GetStatus())
+	}
+	return order, nil
+}
+
+// Status returns the last known snapshot of order `orderID`.
+func (t *OrderTracker) Status(orderID string) (*model.OrderItemV5, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if tr, ok := t.orders[orderID]; ok && tr.order != nil {
+		return tr.order, true
+	}
+	return nil, false
+}
+
+// isTerminalStatus reports whether an order status can no longer change.
+func isTerminalStatus(status string) bool {
+	switch strings.ToLower(status) {
+	case "filled", "cancelled", "canceled", "failed", "rejected", "expired", "deleted":
+		return true
+	}
+	return false
+}
+
+// orderAvgFilledPrice returns the average fill price of `o`, zero when it has
+// no fills or reports none.
+func orderAvgFilledPrice(o *model.OrderItemV5) (float64, error) {
+	for _, item := range o.Items {
+		p, err := parseOptionalFloat("avgFilledPrice", item.AvgFilledPrice)
+		if err != nil {
+			return 0, err
+		}
+		if p > 0 {
+			return p, nil
+		}
+	}
+	q, err := parseOptionalFloat("filledQuantity", o.FilledQuantity)
+	if err != nil || q <= 0 {
+		return 0, err
+	}
+	amount, err := parseOptionalFloat("filledAmount", o.FilledAmount)
+	if err != nil {
+		return 0, err
+	}
+	return amount / q, nil
+}
+
+// fillDelta derives the incremental quantity and price between two cumulative
+// fill snapshots.
+func fillDelta(prevQty, prevAvg, qty, avg float64) (float64, float64) {
+	dq := qty - prevQty
+	if dq <= 0 {
+		return 0, 0
+	}
+	px := (qty*avg - prevQty*prevAvg) / dq
+	if px <= 0 {
+		px = avg
+	}
+	return dq, px
+}
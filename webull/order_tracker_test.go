@@ -0,0 +1,176 @@
+package webull
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+func TestFillDelta(t *testing.T) {
+	asrt := assert.New(t)
+
+	qty, px := fillDelta(0, 0, 10, 5)
+	asrt.Equal(10.0, qty)
+	asrt.Equal(5.0, px)
+
+	// 10 @ 5 then 10 more @ 7 averages to 6
+	qty, px = fillDelta(10, 5, 20, 6)
+	asrt.Equal(10.0, qty)
+	asrt.InDelta(7.0, px, 1e-9)
+
+	qty, px = fillDelta(20, 6, 20, 6)
+	asrt.Equal(0.0, qty)
+	asrt.Equal(0.0, px)
+}
+
+func TestIsTerminalStatus(t *testing.T) {
+	asrt := assert.New(t)
+	asrt.True(isTerminalStatus("Filled"))
+	asrt.True(isTerminalStatus("Cancelled"))
+	asrt.True(isTerminalStatus("Failed"))
+	asrt.False(isTerminalStatus("Working"))
+	asrt.False(isTerminalStatus("Partial Filled"))
+	asrt.False(isTerminalStatus(""))
+}
+
+// orderListServer serves `total` V5 orders, newest first and one second apart,
+// honouring the pageSize and lastCreateTime0 cursor of the order list request.
+type orderListServer struct {
+	total  int
+	status string
+	pages  int
+}
+
+func orderCreateTime(i int) int64 {
+	return time.Date(2023, 3, 1, 15, 0, 0, 0, time.UTC).Add(time.Duration(i)*time.Second).UnixNano() / int64(time.Millisecond)
+}
+
+func (s *orderListServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.pages++
+	if s.status != "" {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = io.WriteString(w, `{"msg":"`+s.status+`","code":"502"}`)
+		return
+	}
+	var input GetOrdersRequest
+	body, _ := io.ReadAll(req.Body)
+	_ = json.Unmarshal(body, &input)
+	orders := make([]string, 0)
+	for i := s.total; i > 0 && len(orders) < input.PageSize; i-- {
+		if input.LastCreateTime0 > 0 && orderCreateTime(i) >= input.LastCreateTime0 {
+			continue
+		}
+		orders = append(orders, fmt.Sprintf(`{"orderId":"%d","status":"Filled","filledQuantity":"1",`+
+			`"items":[{"createTime0":%d,"avgFilledPrice":"10"}]}`, i, orderCreateTime(i)))
+	}
+	_, _ = io.WriteString(w, "["+strings.Join(orders, ",")+"]")
+}
+
+func TestOrderTrackerPollPages(t *testing.T) {
+	asrt := assert.New(t)
+	server := &orderListServer{total: 25}
+	tracker := NewOrderTracker(newTestClient(t, server), 1)
+	tracker.PageSize = 10
+	tracker.Track("3")
+	tracker.Track(strconv.Itoa(24))
+
+	asrt.Empty(tracker.Poll())
+	asrt.Equal(3, server.pages, "order 3 is on the third page")
+	order, ok := tracker.Status("3")
+	asrt.True(ok)
+	asrt.Equal("Filled", order.GetStatus())
+	_, ok = tracker.Status("24")
+	asrt.True(ok)
+
+	// nothing is pending any more
+	asrt.Empty(tracker.Poll())
+	asrt.Equal(3, server.pages)
+}
+
+func TestOrderTrackerRunErrors(t *testing.T) {
+	asrt := assert.New(t)
+	server := &orderListServer{total: 5, status: "unavailable"}
+	tracker := NewOrderTracker(newTestClient(t, server), 1)
+	tracker.PollInterval = time.Millisecond
+	tracker.Track("1")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := tracker.Run(ctx)
+	asrt.Error(err)
+	asrt.NotEqual(context.DeadlineExceeded, err)
+
+	errs := make(chan error, 10)
+	tracker.OnError = func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	asrt.Equal(context.DeadlineExceeded, tracker.Run(ctx))
+	asrt.Error(<-errs)
+}
+
+func TestOrderTrackerPaper(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	asrt.NotNil(c)
+
+	paperAccID, err := c.GetPaperTradeAccountID()
+	asrt.Empty(err)
+	asrt.NotEmpty(paperAccID)
+
+	tickerID, err := c.GetTickerID("SPY")
+	asrt.Empty(err)
+	asrt.NotEmpty(tickerID)
+
+	placed, err := c.PlacePaperOrder(paperAccID, model.PostStockOrderRequest{
+		Action:                    model.PtrOrderSide(model.BUY),
+		ComboType:                 model.PtrComboType("NORMAL"),
+		LmtPrice:                  model.PtrFloat64(1),
+		OrderType:                 model.PtrOrderType(model.LMT),
+		OutsideRegularTradingHour: model.PtrBool(false),
+		Quantity:                  model.PtrFloat64(1),
+		SerialId:                  model.PtrString(c.UUID),
+		TickerId:                  model.PtrInt64(tickerID),
+		TimeInForce:               model.PtrTif(model.DAY),
+	})
+	asrt.Empty(err)
+	asrt.NotEmpty(placed)
+
+	tracker := NewPaperOrderTracker(c, paperAccID)
+	tracker.Track(*placed.OrderId)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*60)
+	defer cancel()
+	go tracker.Run(ctx)
+
+	_, err = c.CancelPaperOrder(paperAccID, *placed.OrderId)
+	asrt.Empty(err)
+
+	order, err := tracker.WaitTerminal(ctx, *placed.OrderId)
+	asrt.Empty(err)
+	asrt.NotNil(order)
+}
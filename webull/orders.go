@@ -1,6 +1,7 @@
 package webull
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -78,6 +79,12 @@ func (c *Client) PlaceOrder(accountID int64, input model.PostStockOrderRequest)
 		response   model.PostOrderResponse
 	)
 
+	reservation, err := c.reserveRisk(TradingAccount{ID: accountID}, input)
+	if err != nil {
+		return nil, err
+	}
+	defer reservation.release()
+
 	if input.SerialId == nil || len(*input.SerialId) == 0 {
 		input.SerialId = &c.UUID
 	}
@@ -91,12 +98,24 @@ func (c *Client) PlaceOrder(accountID int64, input model.PostStockOrderRequest)
 		return nil, err
 	}
 
+	if c.DryRun != nil {
+		if err := validateOrder(input); err != nil {
+			return nil, err
+		}
+		response.OrderId = model.PtrString(c.DryRun.nextID())
+		reservation.commit(*response.OrderId)
+		c.DryRun.record("PlaceOrder", *u, nil, payload, response)
+		return &response, nil
+	}
+
 	err = c.PostAndDecode(*u, &response, &headersMap, nil, payload)
 	if err != nil {
 		return &response, err
 	}
 	if response.OrderId == nil || len(*response.OrderId) == 0 {
 		err = fmt.Errorf("Placed order not confirmed")
+	} else {
+		reservation.commit(*response.OrderId)
 	}
 	return &response, err
 }
@@ -118,6 +137,12 @@ func (c *Client) CheckOtocoOrder(accountID int64, input model.PostOtocoOrderRequ
 		return nil, err
 	}
 
+	if c.DryRun != nil {
+		response := dryRunResponse(c.DryRun.nextID())
+		c.DryRun.record("CheckOtocoOrder", *u, nil, payload, *response)
+		return response, nil
+	}
+
 	err = c.PostAndDecode(*u, &response, &headersMap, nil, payload)
 	if err != nil {
 		return &response, err
@@ -133,6 +158,16 @@ func (c *Client) PlaceOtocoOrder(accountID string, input model.PostOtocoOrderReq
 		response   interface{}
 	)
 
+	account, err := c.riskAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	reservation, err := c.reserveRisk(account, input.NewOrders...)
+	if err != nil {
+		return nil, err
+	}
+	defer reservation.release()
+
 	headersMap[HeaderKeyAccessToken] = c.AccessToken
 	headersMap[HeaderKeyDeviceID] = c.DeviceID
 	headersMap[HeaderKeyTradeToken] = c.TradeToken
@@ -142,10 +177,36 @@ func (c *Client) PlaceOtocoOrder(accountID string, input model.PostOtocoOrderReq
 		return nil, err
 	}
 
-	err = c.PostAndDecode(*u, &response, &headersMap, nil, payload)
+	if c.DryRun != nil {
+		for _, leg := range input.NewOrders {
+			if err := validateOrder(leg); err != nil {
+				return nil, err
+			}
+		}
+		comboID := c.DryRun.nextID()
+		response := dryRunResponse(comboID)
+		reservation.commitCombo(comboID)
+		c.DryRun.record("PlaceOtocoOrder", *u, nil, payload, *response)
+		return response, nil
+	}
+
+	var body json.RawMessage
+	err = c.PostAndDecode(*u, &body, &headersMap, nil, payload)
 	if err != nil {
 		return &response, err
 	}
+	var placed PostComboOrderResponse
+	if err = json.Unmarshal(body, &response); err != nil {
+		return &response, err
+	}
+	if err = json.Unmarshal(body, &placed); err != nil {
+		return &response, err
+	}
+	if placed.ComboId == nil || len(*placed.ComboId) == 0 {
+		err = fmt.Errorf("Placed OTOCO order not confirmed")
+	} else {
+		reservation.commitCombo(*placed.ComboId)
+	}
 	return &response, err
 }
 
@@ -162,10 +223,18 @@ func (c *Client) CancelOrder(accountID, orderID string) (*interface{}, error) {
 	headersMap[HeaderKeyTradeToken] = c.TradeToken
 	headersMap[HeaderKeyTradeTime] = getTimeSeconds()
 
+	if c.DryRun != nil {
+		response := dryRunResponse(orderID)
+		c.riskCancelled(orderID)
+		c.DryRun.record("CancelOrder", *u, nil, nil, *response)
+		return response, nil
+	}
+
 	err := c.PostAndDecode(*u, &response, &headersMap, nil, nil)
 	if err != nil {
 		return &response, err
 	}
+	c.riskCancelled(orderID)
 	return &response, err
 }
 
@@ -177,6 +246,16 @@ func (c *Client) ModifyOrder(accountID string, orderID string, input model.PostS
 	)
 	var response interface{}
 
+	account, err := c.riskAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	reservation, err := c.reserveRiskModify(account, orderID, input)
+	if err != nil {
+		return nil, err
+	}
+	defer reservation.release()
+
 	if input.SerialId == nil || len(*input.SerialId) == 0 {
 		input.SerialId = &c.UUID
 	}
@@ -190,10 +269,21 @@ func (c *Client) ModifyOrder(accountID string, orderID string, input model.PostS
 		return nil, err
 	}
 
+	if c.DryRun != nil {
+		if err := validateOrder(input); err != nil {
+			return nil, err
+		}
+		response := dryRunResponse(orderID)
+		reservation.commit()
+		c.DryRun.record("ModifyOrder", *u, nil, payload, *response)
+		return response, nil
+	}
+
 	err = c.PostAndDecode(*u, &response, &headersMap, nil, payload)
 	if err != nil {
 		return &response, err
 	}
+	reservation.commit()
 	return &response, err
 }
 
@@ -210,36 +300,67 @@ type GetOrdersRequest struct {
 
 // GetOrdersV returns orders.
 func (c *Client) GetOrdersV5(accountID int64, status model.OrderStatus, stTime time.Time, endTime time.Time, count int32) ([]*model.OrderItemV5, error) {
-	var (
-		u, _        = url.Parse(UsTradeEndpointV + "/order/list")
-		response    []model.OrderItemV5
-		headersMap  = make(map[string]string)
-		queryParams = make(map[string]string)
-	)
+	input := newGetOrdersRequest(accountID, status, stTime, count)
+	if endTime.Year() > 2000 {
+		input.LastCreateTime0 = endTime.Unix() * 1000
+		input.EndTimeStr = endTime.Format("2006-01-02")
+	}
 
-	headersMap[HeaderKeyAccessToken] = c.AccessToken
-	headersMap[HeaderKeyDeviceID] = c.DeviceID
-	headersMap[HeaderKeyTradeToken] = c.TradeToken
-	headersMap[HeaderKeyTradeTime] = getTimeSeconds()
+	response, err := c.getOrdersV5Page(input)
+	if err != nil {
+		return nil, err
+	}
+	return filterOrdersByStatus(response, input.Status), nil
+}
 
-	queryParams["secAccountId"] = strconv.FormatInt(accountID, 10)
+// GetOrdersV5Pager walks every page of orders created since `stTime`, newest
+// first, using the `lastCreateTime0` cursor.
+func (c *Client) GetOrdersV5Pager(accountID int64, status model.OrderStatus, stTime time.Time, pageSize int32) *Pager[*model.OrderItemV5] {
+	return NewPager(func(ctx context.Context, cursor string) ([]*model.OrderItemV5, string, error) {
+		page, next, err := timePage(cursor, pageSize, func(before int64, size int32) ([]model.OrderItemV5, error) {
+			input := newGetOrdersRequest(accountID, status, stTime, size)
+			input.LastCreateTime0 = before
+			return c.getOrdersV5Page(input)
+		}, func(o model.OrderItemV5) (string, int64) {
+			return o.GetOrderId(), orderCreateTime0(o)
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		return filterOrdersByStatus(page, string(status)), next, nil
+	})
+}
 
+func newGetOrdersRequest(accountID int64, status model.OrderStatus, stTime time.Time, count int32) GetOrdersRequest {
 	input := GetOrdersRequest{
 		DateType:     "ORDER",
 		PageSize:     int(count),
 		SecAccountID: accountID,
 		Status:       string(status),
 	}
-
 	if stTime.Year() > 2000 {
 		input.StartTimeStr = stTime.Format("2006-01-02")
 	} else {
 		input.StartTimeStr = "2015-01-01"
 	}
-	if endTime.Year() > 2000 {
-		input.LastCreateTime0 = endTime.Unix() * 1000
-		input.EndTimeStr = endTime.Format("2006-01-02")
-	}
+	return input
+}
+
+// getOrdersV5Page fetches a single unfiltered page of orders.
+func (c *Client) getOrdersV5Page(input GetOrdersRequest) ([]model.OrderItemV5, error) {
+	var (
+		u, _        = url.Parse(UsTradeEndpointV + "/order/list")
+		response    []model.OrderItemV5
+		headersMap  = make(map[string]string)
+		queryParams = make(map[string]string)
+	)
+
+	headersMap[HeaderKeyAccessToken] = c.AccessToken
+	headersMap[HeaderKeyDeviceID] = c.DeviceID
+	headersMap[HeaderKeyTradeToken] = c.TradeToken
+	headersMap[HeaderKeyTradeTime] = getTimeSeconds()
+
+	queryParams["secAccountId"] = strconv.FormatInt(input.SecAccountID, 10)
 
 	payload, err := json.Marshal(input)
 	if err != nil {
@@ -250,33 +371,60 @@ func (c *Client) GetOrdersV5(accountID int64, status model.OrderStatus, stTime t
 	if err != nil {
 		return nil, err
 	}
+	return response, nil
+}
 
+// filterOrdersByStatus keeps orders matching `status`, or all of them for model.ALL.
+func filterOrdersByStatus(response []model.OrderItemV5, status string) []*model.OrderItemV5 {
 	rsFiltered := make([]*model.OrderItemV5, 0)
-	if len(response) > 0 {
+	for _, o := range response {
 		// asking for all ?
-		if strings.ToLower(input.Status) != strings.ToLower(string(model.ALL)) {
-			// filter based on status
-			for _, o := range response {
-				if strings.ToLower(o.GetStatus()) == strings.ToLower(input.Status) {
-
-					ord := &model.OrderItemV5{}
-					*ord = o
-					rsFiltered = append(rsFiltered, ord)
-				}
-			}
-		} else {
-			for _, o := range response {
-				ord := &model.OrderItemV5{}
-				*ord = o
-				rsFiltered = append(rsFiltered, ord)
-			}
+		if strings.ToLower(status) != strings.ToLower(string(model.ALL)) &&
+			strings.ToLower(o.GetStatus()) != strings.ToLower(status) {
+			continue
+		}
+		ord := &model.OrderItemV5{}
+		*ord = o
+		rsFiltered = append(rsFiltered, ord)
+	}
+	return rsFiltered
+}
+
+// orderCreateTime0 returns when `o` was created, in milliseconds.
+func orderCreateTime0(o model.OrderItemV5) int64 {
+	for _, item := range o.Items {
+		if ct := item.GetCreateTime0(); ct > 0 {
+			return ct
 		}
 	}
-	return rsFiltered, nil
+	return 0
 }
 
 // GetFilledOrdersByTicker returns orders.
 func (c *Client) GetFilledOrdersByTicker(accountID int64, tickerId int64, lastFillTimeMs int64, count int32) ([]*model.OrderFill, error) {
+	return c.getFilledOrdersPage(accountID, tickerId, lastFillTimeMs, count)
+}
+
+// GetFilledOrdersPager walks every page of fills for ticker `tickerId`, newest
+// first, using the `lastFilledTime` cursor.
+func (c *Client) GetFilledOrdersPager(accountID int64, tickerId int64, pageSize int32) *Pager[*model.OrderFill] {
+	return NewPager(func(ctx context.Context, cursor string) ([]*model.OrderFill, string, error) {
+		return timePage(cursor, pageSize, func(before int64, size int32) ([]*model.OrderFill, error) {
+			return c.getFilledOrdersPage(accountID, tickerId, before, size)
+		}, func(fill *model.OrderFill) (string, int64) {
+			return fillKey(fill), fill.GetFilledTime0()
+		})
+	})
+}
+
+// fillKey identifies a fill within its timestamp. Partial fills of one order
+// share its order ID, so the quantity and price are part of the key.
+func fillKey(fill *model.OrderFill) string {
+	key := fill.GetOrderId() + ":" + fill.GetFilledQuantity() + "@" + fill.GetFilledPrice()
+	return strings.ReplaceAll(key, ",", "")
+}
+
+func (c *Client) getFilledOrdersPage(accountID int64, tickerId int64, lastFillTimeMs int64, count int32) ([]*model.OrderFill, error) {
 	var (
 		u, _        = url.Parse(UsTradeEndpointV + "/order/filledOrders")
 		response    []model.OrderFill
@@ -297,7 +445,7 @@ func (c *Client) GetFilledOrdersByTicker(accountID int64, tickerId int64, lastFi
 	fills := make([]*model.OrderFill, 0)
 	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
 	if err != nil {
-		return fills, nil
+		return fills, err
 	}
 
 	for _, fill := range response {
@@ -305,7 +453,7 @@ func (c *Client) GetFilledOrdersByTicker(accountID int64, tickerId int64, lastFi
 		*of = fill
 		fills = append(fills, of)
 	}
-	return fills, err
+	return fills, nil
 }
 
 type CancelStOrderResponse struct {
@@ -333,10 +481,21 @@ func (c *Client) CancelOrderV5(accountID int64, orderId int64) (bool, error) {
 	queryParams["serialId"] = c.UUID
 	queryParams["orderId"] = fmt.Sprintf("%d", orderId)
 
+	if c.DryRun != nil {
+		response.Result = true
+		response.OrderId = orderId
+		c.riskCancelled(strconv.FormatInt(orderId, 10))
+		c.DryRun.record("CancelOrderV5", *u, &queryParams, nil, response)
+		return response.Result, nil
+	}
+
 	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
 	if err != nil {
 		return false, err
 	}
+	if response.Result {
+		c.riskCancelled(strconv.FormatInt(orderId, 10))
+	}
 
 	return response.Result, nil
 }
@@ -351,6 +510,12 @@ func (c *Client) PlaceOrderV5(accountID int64, input model.PostStockOrderRequest
 
 	queryParams["secAccountId"] = strconv.FormatInt(accountID, 10)
 
+	reservation, err := c.reserveRisk(TradingAccount{ID: accountID}, input)
+	if err != nil {
+		return nil, err
+	}
+	defer reservation.release()
+
 	if input.SerialId == nil || len(*input.SerialId) == 0 {
 		sid := uuid.New().String()
 		input.SerialId = model.PtrString(sid)
@@ -369,12 +534,24 @@ func (c *Client) PlaceOrderV5(accountID int64, input model.PostStockOrderRequest
 		return nil, err
 	}
 
+	if c.DryRun != nil {
+		if err := validateOrder(input); err != nil {
+			return nil, err
+		}
+		response.OrderId = model.PtrString(c.DryRun.nextID())
+		reservation.commit(*response.OrderId)
+		c.DryRun.record("PlaceOrderV5", *u, &queryParams, payload, response)
+		return &response, nil
+	}
+
 	err = c.PostAndDecode(*u, &response, &headersMap, &queryParams, payload)
 	if err != nil {
 		return &response, err
 	}
 	if response.OrderId == nil || len(*response.OrderId) == 0 {
 		err = fmt.Errorf("Placed order not confirmed")
+	} else {
+		reservation.commit(*response.OrderId)
 	}
 	return &response, err
 }
@@ -400,6 +577,19 @@ func (c *Client) PlaceOrderV5Combo(accountID int64,
 	)
 
 	queryParams["secAccountId"] = strconv.FormatInt(accountID, 10)
+
+	legs := make([]model.PostStockOrderRequest, 0, 2)
+	for _, leg := range []*model.PostStockOrderRequest{slOrder, tpOrder} {
+		if leg != nil {
+			legs = append(legs, *leg)
+		}
+	}
+	reservation, err := c.reserveRisk(TradingAccount{ID: accountID}, legs...)
+	if err != nil {
+		return nil, err
+	}
+	defer reservation.release()
+
 	osid := uuid.New().String()
 	pcr := PostComboRequest{
 		Orders:   nil,
@@ -434,12 +624,26 @@ func (c *Client) PlaceOrderV5Combo(accountID int64,
 		return nil, err
 	}
 
+	if c.DryRun != nil {
+		for _, leg := range legs {
+			if err := validateOrder(leg); err != nil {
+				return nil, err
+			}
+		}
+		response.ComboId = model.PtrString(c.DryRun.nextID())
+		reservation.commitCombo(*response.ComboId)
+		c.DryRun.record("PlaceOrderV5Combo", *u, &queryParams, payload, response)
+		return &response, nil
+	}
+
 	err = c.PostAndDecode(*u, &response, &headersMap, &queryParams, payload)
 	if err != nil {
 		return &response, err
 	}
 	if response.ComboId == nil || len(*response.ComboId) == 0 {
 		err = fmt.Errorf("ComboId should not be empty")
+	} else {
+		reservation.commitCombo(*response.ComboId)
 	}
 	return &response, err
 }
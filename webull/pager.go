@@ -1,22 +1,187 @@
 package webull
 
-// import "io"
-
-// // Pager for paginating data
-// type Pager struct {
-// 	Next, Previous string
-// }
-
-// // HasMore for determining when end of pages are reached
-// func (p Pager) HasMore() bool {
-// 	return p.Next != ""
-// }
-
-// // GetNext will decode the next set of pages if they exist
-// func (p *Pager) GetNext(c *Client, out interface{}) error {
-// 	if p.Next == "" {
-// 		return io.EOF
-// 	}
-
-// 	return c.GetAndDecode(p.Next, out)
-// }
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PageFunc fetches the page starting at `cursor` ("" for the first page) and
+// returns its items along with the cursor of the following page. An empty
+// next cursor marks the last page.
+type PageFunc[T any] func(ctx context.Context, cursor string) (items []T, next string, err error)
+
+// Pager for paginating data
+type Pager[T any] struct {
+	fetch   PageFunc[T]
+	cursor  string
+	page    []T
+	started bool
+	done    bool
+}
+
+// NewPager is a constructor for a Pager walking the pages returned by `fetch`.
+func NewPager[T any](fetch PageFunc[T]) *Pager[T] {
+	return &Pager[T]{fetch: fetch}
+}
+
+// HasMore for determining when end of pages are reached
+func (p *Pager[T]) HasMore() bool {
+	return len(p.page) > 0 || !p.done
+}
+
+// Next returns the next item, fetching the following page when the current one
+// is exhausted. It returns io.EOF once every page has been read.
+func (p *Pager[T]) Next(ctx context.Context) (item T, err error) {
+	for len(p.page) == 0 {
+		if p.done {
+			return item, io.EOF
+		}
+		if err = ctx.Err(); err != nil {
+			return item, err
+		}
+		items, next, err := p.fetch(ctx, p.cursor)
+		if err != nil {
+			return item, err
+		}
+		// stop on an empty or repeating cursor so a misbehaving endpoint cannot loop forever
+		if next == "" || (p.started && next == p.cursor) {
+			p.done = true
+		}
+		p.started = true
+		p.cursor = next
+		p.page = items
+	}
+	item, p.page = p.page[0], p.page[1:]
+	return item, nil
+}
+
+// All reads every remaining item.
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	all := make([]T, 0)
+	for {
+		item, err := p.Next(ctx)
+		if err == io.EOF {
+			return all, nil
+		}
+		if err != nil {
+			return all, err
+		}
+		all = append(all, item)
+	}
+}
+
+// maxTimePageGrowth caps how far timePage enlarges a page to get past items
+// sharing a timestamp.
+const maxTimePageGrowth = 64
+
+// timeCursor pages through items listed newest first by a millisecond
+// timestamp. Several items can share a timestamp, so the cursor also keeps the
+// keys already returned at its timestamp: the next page is requested from just
+// after it and those keys are dropped.
+type timeCursor struct {
+	ms   int64
+	seen []string
+}
+
+// parseTimeCursor reads a cursor produced by timeCursor.String.
+func parseTimeCursor(cursor string) (timeCursor, error) {
+	if cursor == "" {
+		return timeCursor{}, nil
+	}
+	parts := strings.Split(cursor, ",")
+	ms, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return timeCursor{}, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return timeCursor{ms: ms, seen: parts[1:]}, nil
+}
+
+func (c timeCursor) String() string {
+	return strings.Join(append([]string{strconv.FormatInt(c.ms, 10)}, c.seen...), ",")
+}
+
+// before is the exclusive upper bound to request the page following the cursor
+// with, or 0 for the first page.
+func (c timeCursor) before() int64 {
+	if c.ms == 0 {
+		return 0
+	}
+	return c.ms + 1
+}
+
+// seenCounts counts the keys already returned at the cursor's timestamp. A key
+// can repeat when several items are indistinguishable, so each is counted.
+func (c timeCursor) seenCounts() map[string]int {
+	counts := make(map[string]int, len(c.seen))
+	for _, s := range c.seen {
+		counts[s]++
+	}
+	return counts
+}
+
+// keep reports whether the item `id` at `ms` has not been returned yet,
+// consuming one of the `seen` counts for `id` when it has.
+func (c timeCursor) keep(id string, ms int64, seen map[string]int) bool {
+	if c.ms == 0 || ms < c.ms {
+		return true
+	}
+	if ms > c.ms {
+		return false
+	}
+	if seen[id] > 0 {
+		seen[id]--
+		return false
+	}
+	return true
+}
+
+// timePage fetches the page following `cursor` through `fetch`, which returns
+// at most `size` items created strictly before `before` (0 for the newest).
+// `key` gives the ID and millisecond timestamp of an item. A full page that is
+// still at the cursor's timestamp is fetched again with a larger size, so runs
+// of items sharing a timestamp are neither skipped nor repeated.
+func timePage[T any](cursor string, pageSize int32, fetch func(before int64, size int32) ([]T, error), key func(T) (string, int64)) ([]T, string, error) {
+	tc, err := parseTimeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	for size := pageSize; ; size *= 2 {
+		response, err := fetch(tc.before(), size)
+		if err != nil {
+			return nil, "", err
+		}
+		items := make([]T, 0, len(response))
+		next := timeCursor{}
+		seen := tc.seenCounts()
+		for _, item := range response {
+			id, ms := key(item)
+			if tc.keep(id, ms, seen) {
+				items = append(items, item)
+			}
+			if ms > 0 && (next.ms == 0 || ms < next.ms) {
+				next.ms = ms
+			}
+		}
+		if len(response) < int(size) {
+			return items, "", nil
+		}
+		if next.ms == 0 {
+			return nil, "", fmt.Errorf("cannot page past %d items without timestamps", len(response))
+		}
+		if next.ms == tc.ms {
+			if size >= pageSize*maxTimePageGrowth {
+				return nil, "", fmt.Errorf("more than %d items share timestamp %d", size, tc.ms)
+			}
+			continue
+		}
+		for _, item := range response {
+			if id, ms := key(item); ms == next.ms {
+				next.seen = append(next.seen, id)
+			}
+		}
+		return items, next.String(), nil
+	}
+}
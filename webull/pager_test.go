@@ -0,0 +1,158 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+func TestPager(t *testing.T) {
+	asrt := assert.New(t)
+	pages := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	calls := 0
+	p := NewPager(func(ctx context.Context, cursor string) ([]int, string, error) {
+		calls++
+		i := 0
+		if cursor != "" {
+			i, _ = strconv.Atoi(cursor)
+		}
+		next := ""
+		if i+1 < len(pages) {
+			next = strconv.Itoa(i + 1)
+		}
+		return pages[i], next, nil
+	})
+
+	first, err := p.Next(context.Background())
+	asrt.Empty(err)
+	asrt.Equal(1, first)
+
+	rest, err := p.All(context.Background())
+	asrt.Empty(err)
+	asrt.Equal([]int{2, 3, 4, 5, 6, 7}, rest)
+	asrt.Equal(3, calls)
+
+	_, err = p.Next(context.Background())
+	asrt.Equal(io.EOF, err)
+	asrt.False(p.HasMore())
+}
+
+func TestPagerRepeatingCursor(t *testing.T) {
+	asrt := assert.New(t)
+	calls := 0
+	p := NewPager(func(ctx context.Context, cursor string) ([]int, string, error) {
+		calls++
+		return []int{calls}, "same", nil
+	})
+	all, err := p.All(context.Background())
+	asrt.Empty(err)
+	asrt.Equal([]int{1, 2}, all)
+}
+
+// fillServer serves fills newest first, several sharing a timestamp, returning
+// those strictly before the lastFilledTime cursor.
+type fillServer struct {
+	times []int64
+	// orders, when set, gives the order ID of each fill instead of its index
+	orders []string
+}
+
+func (s *fillServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	before, _ := strconv.ParseInt(req.URL.Query().Get("lastFilledTime"), 10, 64)
+	size, _ := strconv.Atoi(req.URL.Query().Get("pageSize"))
+	fills := make([]string, 0)
+	for i, ms := range s.times {
+		if (before == 0 || ms < before) && len(fills) < size {
+			id := strconv.Itoa(i)
+			if s.orders != nil {
+				id = s.orders[i]
+			}
+			fills = append(fills, fmt.Sprintf(`{"orderId":"%s","filledQuantity":"%d","filledPrice":"10","filledTime0":%d}`, id, i%2+1, ms))
+		}
+	}
+	_, _ = io.WriteString(w, "["+strings.Join(fills, ",")+"]")
+}
+
+func TestGetFilledOrdersPagerSharedTimestamps(t *testing.T) {
+	asrt := assert.New(t)
+	server := &fillServer{times: []int64{9000, 8000, 8000, 8000, 8000, 7000, 7000, 6000}}
+	c := newTestClient(t, server)
+	fills, err := c.GetFilledOrdersPager(1, 913256135, 3).All(context.Background())
+	asrt.Empty(err)
+	ids := make([]string, 0)
+	for _, fill := range fills {
+		ids = append(ids, fill.GetOrderId())
+	}
+	asrt.Equal([]string{"0", "1", "2", "3", "4", "5", "6", "7"}, ids)
+
+	// partial fills of one order at one timestamp straddle the page boundary
+	server.times = []int64{9000, 8000, 8000, 8000, 8000, 7000}
+	server.orders = []string{"a", "b", "b", "b", "c", "d"}
+	fills, err = c.GetFilledOrdersPager(1, 913256135, 3).All(context.Background())
+	asrt.Empty(err)
+	asrt.Len(fills, 6)
+	ids = make([]string, 0)
+	for _, fill := range fills {
+		ids = append(ids, fill.GetOrderId())
+	}
+	asrt.Equal(server.orders, ids)
+
+	server.times = []int64{0, 0, 0}
+	server.orders = nil
+	_, err = c.GetFilledOrdersPager(1, 913256135, 3).All(context.Background())
+	asrt.Error(err, "a full page without fill times cannot be paged past")
+}
+
+func TestGetOrdersV5Pager(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	asrt.NotNil(c)
+	if accts, err := c.GetAccountsV5(); err != nil {
+		t.Log(err)
+		t.Fail()
+	} else {
+		c.AddSessionHeader(HeaderLzone, *accts.AccountList[0].Rzone)
+		orders, err := c.GetOrdersV5Pager(accts.AccountList[0].GetSecAccountId(), model.FILLED, time.Time{}, 20).All(context.Background())
+		asrt.Empty(err)
+		asrt.NotEmpty(orders)
+	}
+}
+
+func TestGetTransfersPager(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	accountID, err := c.GetAccountID()
+	asrt.Empty(err)
+	transfers, err := c.GetTransfersPager(accountID, 10).All(context.Background())
+	asrt.Empty(err)
+	asrt.NotNil(transfers)
+}
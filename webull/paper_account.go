@@ -2,6 +2,7 @@ package webull
 
 import (
 	"fmt"
+	"math"
 	"net/url"
 	"strconv"
 	"time"
@@ -9,7 +10,8 @@ import (
 	model "quantfu.com/webull/openapi"
 )
 
-// GetPaperTradeAccounts gets information for all paper accounts.
+// GetPaperTradeAccounts gets information for all paper accounts. Webull has no
+// API to open a paper account; additional ones are created in the app.
 func (c *Client) GetPaperTradeAccounts() (*[]model.PaperAccount, error) {
 	var (
 		u, _       = url.Parse(PaperTradeEndpointV + "/myaccounts/true")
@@ -27,8 +29,12 @@ func (c *Client) GetPaperTradeAccounts() (*[]model.PaperAccount, error) {
 	return &response, err
 }
 
-// GetPaperTradeAccountID is a a helper function for getting a single paper trading account ID
+// GetPaperTradeAccountID is a a helper function for getting a single paper trading account ID.
+// It returns the selected account when SelectPaperAccount was called.
 func (c *Client) GetPaperTradeAccountID() (int64, error) {
+	if c.PaperAccountID != 0 {
+		return c.PaperAccountID, nil
+	}
 	res, err := c.GetPaperTradeAccounts()
 	if err != nil {
 		return 0, err
@@ -57,29 +63,93 @@ func (c *Client) GetPaperTradeAccountIDs() ([]int64, error) {
 	}
 }
 
-// ResetPaperAccount gets information for all paper accounts.
-/*
-func (c *Client) ResetPaperAccount(newBalance int32) (*model.ResetPaperAccountResponse, error) {
-	var (
-		headersMap = make(map[string]string)
-		response   model.ResetPaperAccountResponse
-	)
+// SelectPaperAccount makes `accountID` the paper account used by helpers that
+// do not take an account ID, after checking it belongs to the user.
+func (c *Client) SelectPaperAccount(accountID int64) error {
+	ids, err := c.GetPaperTradeAccountIDs()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if id == accountID {
+			c.PaperAccountID = accountID
+			return nil
+		}
+	}
+	return fmt.Errorf("paper account %d not found", accountID)
+}
+
+// ResetPaperAccount resets the selected paper account to `newBalance`.
+func (c *Client) ResetPaperAccount(newBalance int32) (*model.PaperAccountSummary, error) {
 	accID, err := c.GetPaperTradeAccountID()
 	if err != nil {
 		return nil, err
 	}
-	var u, _       = url.Parse(PaperTradeEndpointV + "/paper/1/acc/reset/" + accID + "/" + fmt.Sprintf("%d", newBalance))
+	return c.ResetPaperAccountByID(accID, newBalance)
+}
+
+// ResetPaperAccountByID resets paper account `accountID` to `newBalance`, closing
+// every position and order, then reads the account summary back to confirm it.
+func (c *Client) ResetPaperAccountByID(accountID int64, newBalance int32) (*model.PaperAccountSummary, error) {
+	if newBalance <= 0 {
+		return nil, fmt.Errorf("invalid paper account balance %d", newBalance)
+	}
+	var (
+		path       = PaperTradeEndpointV + "/paper/1/acc/reset/" + strconv.FormatInt(accountID, 10) + "/" + strconv.FormatInt(int64(newBalance), 10)
+		u, _       = url.Parse(path)
+		headersMap = make(map[string]string)
+		response   interface{}
+	)
+
 	headersMap[HeaderKeyAccessToken] = c.AccessToken
 	headersMap[HeaderKeyDeviceID] = c.DeviceID
 
-	err = c.GetAndDecode(*u, &response, &headersMap, nil)
+	if c.DryRun != nil {
+		c.DryRun.record("ResetPaperAccount", *u, nil, nil, *dryRunResponse(""))
+		return &model.PaperAccountSummary{}, nil
+	}
+
+	err := c.GetAndDecode(*u, &response, &headersMap, nil)
 	if err != nil {
-		return &response, err
+		return nil, err
 	}
-	return &response, err
+
+	summary, err := c.GetPaperAccountSummary(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkPaperReset(summary, float64(newBalance)); err != nil {
+		return summary, err
+	}
+	return summary, nil
 }
-*/
 
+// checkPaperReset verifies a freshly reset account holds only `balance` in cash.
+func checkPaperReset(summary *model.PaperAccountSummary, balance float64) error {
+	if len(summary.Positions) > 0 {
+		return fmt.Errorf("paper account reset left %d positions open", len(summary.Positions))
+	}
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{
+		{"netLiquidation", summary.NetLiquidation},
+		{"totalCash", summary.TotalCash},
+		{"usableCash", summary.UsableCash},
+	} {
+		if f.value == nil {
+			continue
+		}
+		value, err := parseFloat(f.name, f.value)
+		if err != nil {
+			return fmt.Errorf("paper account reset: %s", err.Error())
+		}
+		if math.Abs(value-balance) > 0.01 {
+			return fmt.Errorf("paper account %s is %s after reset to %.2f", f.name, *f.value, balance)
+		}
+	}
+	return nil
+}
 func (c *Client) GetNetLiquidationPaper(accountID int64, stTime time.Time) (*[]model.NetLiqidationTrendInner, error) {
 	var (
 		path        = PaperTradeEndpointV + "/paper/1/acc/" + strconv.FormatInt(accountID, 10) + "/accountpl/summary"
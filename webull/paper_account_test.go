@@ -47,12 +47,17 @@ func TestGetPaperTradeAccountID(t *testing.T) {
 	asrt.NotEmpty(paperAccID)
 }
 
-/*
+// TestResetPaperAccount wipes the last paper account, so it only runs when
+// WEBULL_RESET_PAPER_ACCOUNT is set.
 func TestResetPaperAccount(t *testing.T) {
 	if os.Getenv("WEBULL_USERNAME") == "" {
 		t.Skip("No username set")
 		return
 	}
+	if os.Getenv("WEBULL_RESET_PAPER_ACCOUNT") == "" {
+		t.Skip("WEBULL_RESET_PAPER_ACCOUNT not set")
+		return
+	}
 	asrt := assert.New(t)
 	c, err := NewClient(&Credentials{
 		Username:    os.Getenv("WEBULL_USERNAME"),
@@ -62,11 +67,38 @@ func TestResetPaperAccount(t *testing.T) {
 	})
 	asrt.Empty(err)
 	asrt.NotNil(c)
-	paperAccID, err := c.ResetPaperAccount(5000)
+	ids, err := c.GetPaperTradeAccountIDs()
 	asrt.Empty(err)
-	asrt.NotEmpty(paperAccID)
+	asrt.NotEmpty(ids)
+	asrt.Empty(c.SelectPaperAccount(ids[len(ids)-1]))
+	summary, err := c.ResetPaperAccount(5000)
+	asrt.Empty(err)
+	asrt.NotNil(summary)
+	asrt.Empty(summary.Positions)
+}
+
+func TestCheckPaperReset(t *testing.T) {
+	asrt := assert.New(t)
+	asrt.Empty(checkPaperReset(&model.PaperAccountSummary{}, 5000))
+	asrt.Error(checkPaperReset(&model.PaperAccountSummary{Positions: []model.PaperPosition{{}}}, 5000))
+	asrt.Empty(checkPaperReset(&model.PaperAccountSummary{NetLiquidation: model.PtrString("5000.00")}, 5000))
+	asrt.Error(checkPaperReset(&model.PaperAccountSummary{UsableCash: model.PtrString("1,000,000.00")}, 5000))
+}
+
+func TestResetPaperAccountDryRun(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	c.DryRun = NewDryRun()
+	c.DryRun.Log = func(DryRunRecord) {}
+	_, err = c.ResetPaperAccountByID(12345, 5000)
+	asrt.Empty(err)
+	records := c.DryRun.Records()
+	asrt.Len(records, 1)
+	asrt.Contains(records[0].URL, "/paper/1/acc/reset/12345/5000")
+	_, err = c.ResetPaperAccountByID(12345, 0)
+	asrt.Error(err)
 }
-*/
 
 func TestGetNetLiquidationPaper(t *testing.T) {
 	if os.Getenv("WEBULL_USERNAME") == "" {
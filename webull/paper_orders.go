@@ -1,8 +1,10 @@
 package webull
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/url"
 	"strconv"
 	"strings"
@@ -11,28 +13,17 @@ import (
 	model "quantfu.com/webull/openapi"
 )
 
-// CancelAllPaperOrders is a wrapper for cancelling a number of WORKING orders.
-// Note: no pagination so no guarantee all orders will cancel
+// CancelAllPaperOrders is a wrapper for cancelling all WORKING orders. It returns
+// the IDs of orders confirmed cancelled, see CancelAll for per-order results.
 func (c *Client) CancelAllPaperOrders(accountID int64) ([]string, error) {
-	if paperOrders, err := c.GetPaperOrders(accountID, model.WORKING, time.Unix(0, 0), 200); err != nil {
-		return nil, err
-	} else if paperOrders == nil {
-		return nil, fmt.Errorf("no orders returned")
-	} else {
-		cancelledOrders := make([]string, 0)
-		for _, order := range paperOrders {
-			cancellation, err := c.CancelPaperOrder(accountID, *order.OrderId)
-			if err != nil {
-				fmt.Printf("TODO: fix marshalling error\n")
-				cancelledOrders = append(cancelledOrders, *order.OrderId)
-				//return cancelledOrders, err
-			} else {
-				cancelledOrders = append(cancelledOrders, *order.OrderId)
-			}
-			fmt.Printf("cancellation: %v", cancellation)
+	results, err := c.CancelAll(context.Background(), TradingAccount{ID: accountID, Paper: true}, CancelFilter{})
+	cancelledOrders := make([]string, 0, len(results))
+	for _, r := range results {
+		if r.Cancelled {
+			cancelledOrders = append(cancelledOrders, r.OrderID)
 		}
-		return cancelledOrders, nil
 	}
+	return cancelledOrders, err
 }
 
 // PlacePaperOrder places paper trade
@@ -43,6 +34,12 @@ func (c *Client) PlacePaperOrder(accountID int64, input model.PostStockOrderRequ
 		response   model.PostOrderResponse
 	)
 
+	reservation, err := c.reserveRisk(TradingAccount{ID: accountID, Paper: true}, input)
+	if err != nil {
+		return nil, err
+	}
+	defer reservation.release()
+
 	if input.SerialId == nil || len(*input.SerialId) == 0 {
 		input.SerialId = &c.UUID
 	}
@@ -55,10 +52,25 @@ func (c *Client) PlacePaperOrder(accountID int64, input model.PostStockOrderRequ
 		return nil, err
 	}
 
+	if c.DryRun != nil {
+		if err := validateOrder(input); err != nil {
+			return nil, err
+		}
+		response.OrderId = model.PtrString(c.DryRun.nextID())
+		reservation.commit(*response.OrderId)
+		c.DryRun.record("PlacePaperOrder", *u, nil, payload, response)
+		return &response, nil
+	}
+
 	err = c.PostAndDecode(*u, &response, &headersMap, nil, payload)
 	if err != nil {
 		return &response, err
 	}
+	if response.OrderId == nil || len(*response.OrderId) == 0 {
+		err = fmt.Errorf("Placed order not confirmed")
+	} else {
+		reservation.commit(*response.OrderId)
+	}
 	return &response, err
 }
 
@@ -74,10 +86,18 @@ func (c *Client) CancelPaperOrder(accountID int64, oid string) (*interface{}, er
 	headersMap[HeaderKeyDeviceID] = c.DeviceID
 	headersMap[HeaderKeyTradeToken] = c.TradeToken
 
+	if c.DryRun != nil {
+		response := dryRunResponse(oid)
+		c.riskCancelled(oid)
+		c.DryRun.record("CancelPaperOrder", *u, nil, nil, *response)
+		return response, nil
+	}
+
 	err := c.PostAndDecode(*u, &response, &headersMap, nil, nil)
 	if err != nil {
 		return &response, err
 	}
+	c.riskCancelled(oid)
 	return &response, err
 }
 
@@ -89,6 +109,12 @@ func (c *Client) ModifyPaperOrder(accountID int64, orderID string, input model.P
 	)
 	var response interface{}
 
+	reservation, err := c.reserveRiskModify(TradingAccount{ID: accountID, Paper: true}, orderID, input)
+	if err != nil {
+		return nil, err
+	}
+	defer reservation.release()
+
 	if input.SerialId == nil || len(*input.SerialId) == 0 {
 		input.SerialId = &c.UUID
 	}
@@ -101,15 +127,52 @@ func (c *Client) ModifyPaperOrder(accountID int64, orderID string, input model.P
 		return nil, err
 	}
 
+	if c.DryRun != nil {
+		if err := validateOrder(input); err != nil {
+			return nil, err
+		}
+		response := dryRunResponse(orderID)
+		reservation.commit()
+		c.DryRun.record("ModifyPaperOrder", *u, nil, payload, *response)
+		return response, nil
+	}
+
 	err = c.PostAndDecode(*u, &response, &headersMap, nil, payload)
 	if err != nil {
 		return &response, err
 	}
+	reservation.commit()
 	return &response, err
 }
 
 // GetPaperOrders gets user paper trades
 func (c *Client) GetPaperOrders(paperAccountID int64, orderStatus model.OrderStatus, stTime time.Time, count int32) ([]*model.OrderItemV5, error) {
+	response, err := c.getPaperOrdersPage(paperAccountID, orderStatus, stTime, 0, count)
+	if err != nil {
+		return make([]*model.OrderItemV5, 0), err
+	}
+	return c.filterPaperOrdersByStatus(response, orderStatus)
+}
+
+// GetPaperOrdersPager walks every page of paper orders created since `stTime`,
+// newest first, using the `lastCreateTime0` cursor.
+func (c *Client) GetPaperOrdersPager(paperAccountID int64, orderStatus model.OrderStatus, stTime time.Time, pageSize int32) *Pager[*model.OrderItemV5] {
+	return NewPager(func(ctx context.Context, cursor string) ([]*model.OrderItemV5, string, error) {
+		page, next, err := timePage(cursor, pageSize, func(before int64, size int32) ([]model.PaperOrder, error) {
+			return c.getPaperOrdersPage(paperAccountID, orderStatus, stTime, before, size)
+		}, func(o model.PaperOrder) (string, int64) {
+			return o.GetOrderId(), o.GetCreateTime0()
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		orders, err := c.filterPaperOrdersByStatus(page, orderStatus)
+		return orders, next, err
+	})
+}
+
+// getPaperOrdersPage fetches a single unfiltered page of paper orders.
+func (c *Client) getPaperOrdersPage(paperAccountID int64, orderStatus model.OrderStatus, stTime time.Time, lastCreateTime0 int64, count int32) ([]model.PaperOrder, error) {
 	var (
 		u, _       = url.Parse(PaperTradeEndpointV + "/paper/1/acc/" + strconv.FormatInt(paperAccountID, 10) + "/order")
 		headersMap = make(map[string]string)
@@ -128,40 +191,96 @@ func (c *Client) GetPaperOrders(paperAccountID int64, orderStatus model.OrderSta
 	urlMap["dateType"] = strings.ToUpper(string(orderStatus))
 	urlMap["pageSize"] = strconv.FormatInt(int64(count), 10)
 	urlMap["status"] = string(orderStatus)
+	if lastCreateTime0 > 0 {
+		urlMap["lastCreateTime0"] = strconv.FormatInt(lastCreateTime0, 10)
+	}
 	err := c.GetAndDecode(*u, &response, &headersMap, &urlMap)
-
-	rsFiltered := make([]*model.OrderItemV5, 0)
 	if err != nil {
-		return rsFiltered, err
+		return nil, err
 	}
+	return response, nil
+}
 
-	if len(response) > 0 {
+// filterPaperOrdersByStatus converts paper orders matching `orderStatus`, or all of them for model.ALL.
+func (c *Client) filterPaperOrdersByStatus(response []model.PaperOrder, orderStatus model.OrderStatus) ([]*model.OrderItemV5, error) {
+	rsFiltered := make([]*model.OrderItemV5, 0)
+	for _, o := range response {
 		// asking for all ?
-		if strings.ToLower(string(orderStatus)) != strings.ToLower(string(model.ALL)) {
-			// filter based on status
-			for _, o := range response {
-				if strings.ToLower(o.GetStatus()) == strings.ToLower(string(orderStatus)) {
-					ord := c.toOrderItemV5(o)
-					rsFiltered = append(rsFiltered, ord)
-				}
-			}
-		} else {
-			for _, o := range response {
-				ord := c.toOrderItemV5(o)
-				rsFiltered = append(rsFiltered, ord)
-			}
+		if strings.ToLower(string(orderStatus)) != strings.ToLower(string(model.ALL)) &&
+			strings.ToLower(o.GetStatus()) != strings.ToLower(string(orderStatus)) {
+			continue
+		}
+		ord, err := c.toOrderItemV5(o)
+		if err != nil {
+			return nil, err
 		}
+		rsFiltered = append(rsFiltered, ord)
 	}
 	return rsFiltered, nil
 }
 
-func (c *Client) toOrderItemV5(o model.PaperOrder) *model.OrderItemV5 {
+// optionContractMultiplier is the number of shares one option contract covers.
+const optionContractMultiplier = 100
+
+// toOrderItemV5 maps a paper order into the V5 order representation used by
+// live accounts. Values a paper order leaves out, like remaining quantity and
+// amounts, are derived from the quantities and prices it does carry; option
+// amounts are per contract, so include the contract multiplier.
+func (c *Client) toOrderItemV5(o model.PaperOrder) (*model.OrderItemV5, error) {
+	var (
+		multiplier                    = 1.0
+		filled, avgPrice, price, stop float64
+		remaining                     = o.RemainQuantity
+		filledAmount                  = o.FilledAmount
+		placeAmount                   = o.PlaceAmount
+	)
+	total, err := parseFloat("totalQuantity", o.TotalQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("paper order %s: %s", o.GetOrderId(), err.Error())
+	}
+	for _, f := range []struct {
+		name  string
+		value *string
+		dest  *float64
+	}{
+		{"filledQuantity", o.FilledQuantity, &filled},
+		{"avgFilledPrice", o.AvgFilledPrice, &avgPrice},
+		{"lmtPrice", o.LmtPrice, &price},
+		{"auxPrice", o.AuxPrice, &stop},
+	} {
+		if *f.dest, err = parseOptionalFloat(f.name, f.value); err != nil {
+			return nil, fmt.Errorf("paper order %s: %s", o.GetOrderId(), err.Error())
+		}
+	}
+	if price == 0 {
+		price = stop
+	}
+	if paperComboTickerType(o.Ticker.GetTemplate()) == "option" {
+		multiplier = optionContractMultiplier
+	}
+	if remaining == nil && total > 0 {
+		remaining = model.PtrString(formatAmount(math.Max(total-filled, 0)))
+	}
+	if filledAmount == nil && filled > 0 && avgPrice > 0 {
+		filledAmount = model.PtrString(formatAmount(filled * avgPrice * multiplier))
+	}
+	if placeAmount == nil && total > 0 && price > 0 {
+		placeAmount = model.PtrString(formatAmount(total * price * multiplier))
+	}
+	comboTickerType := o.ComboTickerType
+	if comboTickerType == nil {
+		comboTickerType = model.PtrString(paperComboTickerType(o.Ticker.GetTemplate()))
+	}
+
 	ord := &model.OrderItemV5{
 		OrderId:                   o.OrderId,
+		ComboId:                   o.ComboId,
+		ComboType:                 o.ComboType,
+		ComboTickerType:           comboTickerType,
 		OutsideRegularTradingHour: o.OutsideRegularTradingHour,
-		Quantity:                  o.FilledQuantity,
+		Quantity:                  o.TotalQuantity,
 		FilledQuantity:            o.FilledQuantity,
-		FilledAmount:              nil,
+		FilledAmount:              filledAmount,
 		Action:                    o.Action,
 		Status:                    o.Status,
 		StatusName:                o.StatusStr,
@@ -170,30 +289,19 @@ func (c *Client) toOrderItemV5(o model.PaperOrder) *model.OrderItemV5 {
 		CanModify:                 o.CanModify,
 		CanCancel:                 o.CanCancel,
 		LmtPrice:                  o.LmtPrice,
-		AuxPrice:                  nil,
-		Items:                     nil,
-		FilledTotalAmount:         nil,
-		TotalAmount:               nil,
+		AuxPrice:                  o.AuxPrice,
+		FilledTotalAmount:         filledAmount,
+		TotalAmount:               placeAmount,
 	}
-
-	// currently all paper is stock
-	ord.ComboTickerType = model.PtrString("stock")
-
-	ord.Items = make([]model.OrderItemV5ItemsInner, 1)
-	ord.Items[0] = model.OrderItemV5ItemsInner{
-		BrokerId:                  nil,
+	item := model.OrderItemV5ItemsInner{
 		OrderId:                   o.OrderId,
-		BrokerOrderId:             nil,
-		TickerType:                o.Ticker.Template,
 		Ticker:                    o.Ticker,
 		Action:                    o.Action,
 		OrderType:                 o.OrderType,
 		TotalQuantity:             o.TotalQuantity,
-		TickerId:                  o.Ticker.TickerId,
 		TimeInForce:               o.TimeInForce,
 		FilledQuantity:            o.FilledQuantity,
 		StatusName:                o.StatusStr,
-		Symbol:                    o.Ticker.Symbol,
 		CreateTime0:               o.CreateTime0,
 		CreateTime:                o.CreateTime,
 		FilledTime0:               o.FilledTime0,
@@ -201,12 +309,31 @@ func (c *Client) toOrderItemV5(o model.PaperOrder) *model.OrderItemV5 {
 		AvgFilledPrice:            o.AvgFilledPrice,
 		CanModify:                 o.CanModify,
 		CanCancel:                 o.CanCancel,
-		AssetType:                 o.Ticker.Template,
-		RemainQuantity:            nil,
-		PlaceAmount:               nil,
-		FilledAmount:              nil,
+		RemainQuantity:            remaining,
+		PlaceAmount:               placeAmount,
+		FilledAmount:              filledAmount,
 		OutsideRegularTradingHour: o.OutsideRegularTradingHour,
-		AmOrPm:                    nil,
 	}
-	return ord
+	if o.Ticker != nil {
+		item.TickerId = o.Ticker.TickerId
+		item.Symbol = o.Ticker.Symbol
+		item.TickerType = o.Ticker.Template
+		item.AssetType = o.Ticker.Template
+	}
+	ord.Items = []model.OrderItemV5ItemsInner{item}
+	return ord, nil
+}
+
+// formatAmount formats a derived quantity or amount the way Webull sends them.
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// paperComboTickerType picks the V5 combo ticker type for a paper order on a
+// ticker with the given template.
+func paperComboTickerType(template string) string {
+	if strings.EqualFold(template, "option") {
+		return "option"
+	}
+	return "stock"
 }
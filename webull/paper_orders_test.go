@@ -1,6 +1,7 @@
 package webull
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
@@ -190,3 +191,141 @@ func TestModifyPaperOrder(t *testing.T) {
 	})
 	asrt.Empty(err)
 }
+
+// paperOrderFixtures are hand-written paper orders covering limit, stop,
+// option and bracket orders. TestToOrderItemV5RoundTripLive runs the same
+// checks on orders returned by the paper orders endpoint.
+var paperOrderFixtures = map[string]string{
+	"partialLimit": `{"orderId":"1001","action":"BUY","orderType":"LMT","timeInForce":"DAY",
+		"status":"Working","statusStr":"Partially Filled","lmtPrice":"150.5","totalQuantity":"10",
+		"filledQuantity":"4","avgFilledPrice":"150.25","outsideRegularTradingHour":false,
+		"canModify":true,"canCancel":true,"createTime0":1669852800000,"createTime":"12/01/2022 00:00:00 GMT",
+		"ticker":{"tickerId":913256135,"symbol":"AAPL","template":"stock"}}`,
+	"stop": `{"orderId":"1002","action":"SELL","orderType":"STP","timeInForce":"GTC",
+		"status":"Working","statusStr":"Working","auxPrice":"140","totalQuantity":"5",
+		"filledQuantity":"0","canModify":true,"canCancel":true,"createTime0":1669852900000,
+		"ticker":{"tickerId":913256135,"symbol":"AAPL","template":"stock"}}`,
+	"option": `{"orderId":"1003","action":"BUY","orderType":"LMT","timeInForce":"DAY",
+		"status":"Filled","statusStr":"Filled","lmtPrice":"2.5","totalQuantity":"2",
+		"filledQuantity":"2","avgFilledPrice":"2.45","canModify":false,"canCancel":false,
+		"createTime0":1669853000000,"filledTime0":1669853005000,
+		"ticker":{"tickerId":1038117461,"symbol":"AAPL221216C00150000","template":"option"}}`,
+	"bracketLeg": `{"orderId":"1004","comboId":"77","comboType":"STOP_LOSS","action":"SELL",
+		"orderType":"STP","timeInForce":"GTC","status":"Working","statusStr":"Working",
+		"auxPrice":"95","totalQuantity":"3","filledQuantity":"0","canModify":true,"canCancel":true,
+		"createTime0":1669853100000,"ticker":{"tickerId":913255598,"symbol":"SPY","template":"etf"}}`,
+}
+
+// paperOrderFromV5 maps a converted order back, keeping only values the
+// paper order carried, so a round trip can be compared field by field.
+func paperOrderFromV5(ord *model.OrderItemV5, src model.PaperOrder) model.PaperOrder {
+	item := ord.Items[0]
+	back := model.PaperOrder{
+		OrderId:                   ord.OrderId,
+		OutsideRegularTradingHour: ord.OutsideRegularTradingHour,
+		TotalQuantity:             ord.Quantity,
+		FilledQuantity:            ord.FilledQuantity,
+		Action:                    ord.Action,
+		Status:                    ord.Status,
+		StatusStr:                 ord.StatusName,
+		TimeInForce:               ord.TimeInForce,
+		OrderType:                 ord.OrderType,
+		CanModify:                 ord.CanModify,
+		CanCancel:                 ord.CanCancel,
+		LmtPrice:                  ord.LmtPrice,
+		AuxPrice:                  ord.AuxPrice,
+		ComboId:                   ord.ComboId,
+		ComboType:                 ord.ComboType,
+		Ticker:                    item.Ticker,
+		CreateTime0:               item.CreateTime0,
+		CreateTime:                item.CreateTime,
+		FilledTime0:               item.FilledTime0,
+		FilledTime:                item.FilledTime,
+		AvgFilledPrice:            item.AvgFilledPrice,
+	}
+	// derived by toOrderItemV5 when the paper order leaves them out
+	if src.RemainQuantity != nil {
+		back.RemainQuantity = item.RemainQuantity
+	}
+	if src.FilledAmount != nil {
+		back.FilledAmount = ord.FilledAmount
+	}
+	if src.PlaceAmount != nil {
+		back.PlaceAmount = item.PlaceAmount
+	}
+	if src.ComboTickerType != nil {
+		back.ComboTickerType = ord.ComboTickerType
+	}
+	return back
+}
+
+// checkPaperOrderRoundTrip converts `paper` to V5, through JSON and back.
+func checkPaperOrderRoundTrip(asrt *assert.Assertions, c *Client, name string, paper model.PaperOrder) *model.OrderItemV5 {
+	converted, err := c.toOrderItemV5(paper)
+	asrt.Empty(err, name)
+	payload, err := json.Marshal(converted)
+	asrt.Empty(err, name)
+	var ord model.OrderItemV5
+	asrt.Empty(json.Unmarshal(payload, &ord), name)
+	if !asrt.Len(ord.Items, 1, name) {
+		return &ord
+	}
+	asrt.Equal(paper, paperOrderFromV5(&ord, paper), name)
+
+	item := ord.Items[0]
+	asrt.Equal(paper.Ticker.GetTickerId(), item.GetTickerId(), name)
+	asrt.Equal(paper.Ticker.GetSymbol(), item.GetSymbol(), name)
+	asrt.Equal(paper.Ticker.GetTemplate(), item.GetTickerType(), name)
+	if paper.RemainQuantity == nil {
+		filled, err := parseOptionalFloat("filledQuantity", paper.FilledQuantity)
+		asrt.Empty(err, name)
+		asrt.Equal(testFloat(asrt, paper.TotalQuantity)-filled, testFloat(asrt, item.RemainQuantity), name)
+	}
+	return &ord
+}
+
+func TestToOrderItemV5RoundTrip(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+
+	orders := make(map[string]*model.OrderItemV5)
+	for name, payload := range paperOrderFixtures {
+		var paper model.PaperOrder
+		asrt.Empty(json.Unmarshal([]byte(payload), &paper), name)
+		orders[name] = checkPaperOrderRoundTrip(asrt, c, name, paper)
+	}
+
+	asrt.Equal("stock", orders["partialLimit"].GetComboTickerType())
+	asrt.InDelta(601.0, testFloat(asrt, orders["partialLimit"].FilledAmount), 0.001)
+	asrt.InDelta(1505.0, testFloat(asrt, orders["partialLimit"].TotalAmount), 0.001)
+	asrt.Equal("option", orders["option"].GetComboTickerType())
+	// 2 contracts of 100 shares
+	asrt.InDelta(490.0, testFloat(asrt, orders["option"].FilledAmount), 0.001)
+	asrt.InDelta(500.0, testFloat(asrt, orders["option"].TotalAmount), 0.001)
+	asrt.InDelta(700.0, testFloat(asrt, orders["stop"].TotalAmount), 0.001)
+	asrt.Equal("77", orders["bracketLeg"].GetComboId())
+	asrt.Equal("STOP_LOSS", orders["bracketLeg"].GetComboType())
+}
+
+func TestToOrderItemV5RoundTripLive(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	paperAccID, err := c.GetPaperTradeAccountID()
+	asrt.Empty(err)
+	orders, err := c.getPaperOrdersPage(paperAccID, model.ALL, time.Unix(0, 0), 0, 100)
+	asrt.Empty(err)
+	for _, paper := range orders {
+		checkPaperOrderRoundTrip(asrt, c, paper.GetOrderId(), paper)
+	}
+}
@@ -0,0 +1,806 @@
+package webull
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	model "quantfu.com/webull/openapi"
+)
+
+// DefaultSimAccountID is the paper account NewPaperSimulator opens.
+const DefaultSimAccountID int64 = 1
+
+// Order statuses reported by the PaperSimulator, matching the paper center.
+const (
+	simStatusWorking   = "Working"
+	simStatusFilled    = "Filled"
+	simStatusCancelled = "Cancelled"
+	simStatusFailed    = "Failed"
+	simStatusExpired   = "Expired"
+)
+
+// PaperBroker is the paper trading surface shared by Client and PaperSimulator,
+// so code written against one runs against the other.
+type PaperBroker interface {
+	PlacePaperOrder(accountID int64, input model.PostStockOrderRequest) (*model.PostOrderResponse, error)
+	CancelPaperOrder(accountID int64, oid string) (*interface{}, error)
+	ModifyPaperOrder(accountID int64, orderID string, input model.PostStockOrderRequest) (*interface{}, error)
+	GetPaperOrders(paperAccountID int64, orderStatus model.OrderStatus, stTime time.Time, count int32) ([]*model.OrderItemV5, error)
+	GetPaperAccountSummary(accountID int64) (*model.PaperAccountSummary, error)
+}
+
+var (
+	_ PaperBroker = (*Client)(nil)
+	_ PaperBroker = (*PaperSimulator)(nil)
+)
+
+// SimQuote is a market snapshot orders are matched against. Zero fields are
+// unknown. High and Low only apply to the update they arrive with, so a bar
+// can trigger limits and stops its close never reached.
+type SimQuote struct {
+	Time time.Time
+	Last float64
+	Bid  float64
+	Ask  float64
+	High float64
+	Low  float64
+}
+
+// SimPosition is a simulated holding, valued at the last known price.
+type SimPosition struct {
+	TickerID  int64
+	Quantity  float64
+	AvgCost   float64
+	LastPrice float64
+	// RealizedPnL excludes commissions, which are charged to cash.
+	RealizedPnL float64
+}
+
+// MarketValue of the position at its last price.
+func (p SimPosition) MarketValue() float64 {
+	return p.Quantity * p.LastPrice
+}
+
+// UnrealizedPnL of the position at its last price.
+func (p SimPosition) UnrealizedPnL() float64 {
+	return p.Quantity * (p.LastPrice - p.AvgCost)
+}
+
+// SimFill is an execution produced by the PaperSimulator.
+type SimFill struct {
+	AccountID  int64
+	OrderID    string
+	TickerID   int64
+	Action     model.OrderSide
+	Quantity   float64
+	Price      float64
+	Commission float64
+	Time       time.Time
+}
+
+// PaperSimulator is an offline matching engine implementing the paper trading
+// API. Orders fill against quotes fed through UpdateQuote or HandleQuote, from
+// the live stream or a replay. Accounts are cash accounts: buys must be covered
+// by cash and sells by shares held, so positions never go short. Orders fill in
+// full. The simulator's clock is the time of the latest quote, so orders are
+// only accepted once a quote with a time has arrived.
+type PaperSimulator struct {
+	// CommissionPerShare and MinCommission price every fill.
+	CommissionPerShare float64
+	MinCommission      float64
+	// SlippageBps moves market and stop fills against the order, in basis points.
+	SlippageBps float64
+	// OnFill, when set, is called for every fill, with the simulator unlocked.
+	OnFill func(SimFill)
+
+	mu       sync.Mutex
+	seq      int64
+	now      time.Time
+	quotes   map[int64]SimQuote
+	accounts map[int64]*simAccount
+}
+
+type simAccount struct {
+	cash      float64
+	positions map[int64]*SimPosition
+	orders    []*simOrder
+	fills     []SimFill
+}
+
+type simOrder struct {
+	id        string
+	accountID int64
+	input     model.PostStockOrderRequest
+	status    string
+	triggered bool
+	filledQty float64
+	avgPrice  float64
+	created   time.Time
+	filled    time.Time
+}
+
+// NewPaperSimulator is a constructor for a PaperSimulator with a single account,
+// DefaultSimAccountID, holding `cash`.
+func NewPaperSimulator(cash float64) *PaperSimulator {
+	s := &PaperSimulator{
+		quotes:   make(map[int64]SimQuote),
+		accounts: make(map[int64]*simAccount),
+	}
+	s.AddAccount(DefaultSimAccountID, cash)
+	return s
+}
+
+// AddAccount opens, or resets, paper account `accountID` with `cash`.
+func (s *PaperSimulator) AddAccount(accountID int64, cash float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[accountID] = &simAccount{cash: cash, positions: make(map[int64]*SimPosition)}
+}
+
+// Cash held by `accountID`.
+func (s *PaperSimulator) Cash(accountID int64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if acc, ok := s.accounts[accountID]; ok {
+		return acc.cash
+	}
+	return 0
+}
+
+// Positions open in `accountID`, ordered by ticker ID.
+func (s *PaperSimulator) Positions(accountID int64) []SimPosition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, ok := s.accounts[accountID]
+	if !ok {
+		return nil
+	}
+	return acc.openPositions()
+}
+
+// Fills executed in `accountID`, oldest first.
+func (s *PaperSimulator) Fills(accountID int64) []SimFill {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, ok := s.accounts[accountID]
+	if !ok {
+		return nil
+	}
+	out := make([]SimFill, len(acc.fills))
+	copy(out, acc.fills)
+	return out
+}
+
+// NetLiquidation is the cash plus market value of `accountID`.
+func (s *PaperSimulator) NetLiquidation(accountID int64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, ok := s.accounts[accountID]
+	if !ok {
+		return 0
+	}
+	value := acc.cash
+	for _, p := range acc.positions {
+		value += p.MarketValue()
+	}
+	return value
+}
+
+// PlacePaperOrder accepts an order and matches it against the latest quote.
+func (s *PaperSimulator) PlacePaperOrder(accountID int64, input model.PostStockOrderRequest) (*model.PostOrderResponse, error) {
+	if err := validateOrder(input); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	acc, err := s.account(accountID)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if err = s.checkOrder(acc, nil, input); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.seq++
+	o := &simOrder{
+		id:        strconv.FormatInt(s.seq, 10),
+		accountID: accountID,
+		input:     input,
+		status:    simStatusWorking,
+		created:   s.now,
+	}
+	acc.orders = append(acc.orders, o)
+	fills := s.match(o)
+	s.mu.Unlock()
+
+	s.notify(fills)
+	return &model.PostOrderResponse{OrderId: model.PtrString(o.id)}, nil
+}
+
+// CancelPaperOrder cancels a working order.
+func (s *PaperSimulator) CancelPaperOrder(accountID int64, oid string) (*interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, err := s.workingOrder(accountID, oid)
+	if err != nil {
+		return nil, err
+	}
+	o.status = simStatusCancelled
+	return simResponse(oid), nil
+}
+
+// ModifyPaperOrder replaces the terms of a working order and matches it again.
+func (s *PaperSimulator) ModifyPaperOrder(accountID int64, orderID string, input model.PostStockOrderRequest) (*interface{}, error) {
+	if err := validateOrder(input); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	o, err := s.workingOrder(accountID, orderID)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if input.GetTickerId() != o.input.GetTickerId() {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("cannot change ticker of order %s", orderID)
+	}
+	if err = s.checkOrder(s.accounts[accountID], o, input); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	o.input = input
+	o.triggered = false
+	fills := s.match(o)
+	s.mu.Unlock()
+
+	s.notify(fills)
+	return simResponse(orderID), nil
+}
+
+// GetPaperOrders lists orders newest first, like the paper center.
+func (s *PaperSimulator) GetPaperOrders(paperAccountID int64, orderStatus model.OrderStatus, stTime time.Time, count int32) ([]*model.OrderItemV5, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, err := s.account(paperAccountID)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]*model.OrderItemV5, 0)
+	for i := len(acc.orders) - 1; i >= 0; i-- {
+		o := acc.orders[i]
+		if stTime.Year() > 2000 && o.created.Before(stTime) {
+			continue
+		}
+		if !strings.EqualFold(string(orderStatus), string(model.ALL)) && !strings.EqualFold(string(orderStatus), o.status) {
+			continue
+		}
+		rs = append(rs, o.toOrderItemV5())
+		if count > 0 && len(rs) >= int(count) {
+			break
+		}
+	}
+	return rs, nil
+}
+
+// GetPaperAccountSummary reports cash and positions in the shape of the paper
+// center's account summary.
+func (s *PaperSimulator) GetPaperAccountSummary(accountID int64) (*model.PaperAccountSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, err := s.account(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	var marketValue, unrealized, cost float64
+	summary := model.PaperAccountSummary{
+		Positions: make([]model.PaperPosition, 0, len(acc.positions)),
+	}
+	for _, p := range acc.openPositions() {
+		marketValue += p.MarketValue()
+		unrealized += p.UnrealizedPnL()
+		cost += p.Quantity * p.AvgCost
+		position := model.PaperPosition{
+			TickerType:           model.PtrString("EQUITY"),
+			Ticker:               &model.Ticker{TickerId: model.PtrInt64(p.TickerID), Template: model.PtrString("stock")},
+			Position:             model.PtrString(formatAmount(p.Quantity)),
+			CostPrice:            model.PtrString(formatAmount(p.AvgCost)),
+			TotalCost:            model.PtrString(formatAmount(p.Quantity * p.AvgCost)),
+			LastPrice:            model.PtrString(formatAmount(p.LastPrice)),
+			MarketValue:          model.PtrString(formatAmount(p.MarketValue())),
+			UnrealizedProfitLoss: model.PtrString(formatAmount(p.UnrealizedPnL())),
+		}
+		position.SetAssetType("stock")
+		summary.Positions = append(summary.Positions, position)
+	}
+	summary.NetLiquidation = model.PtrString(formatAmount(acc.cash + marketValue))
+	summary.TotalCash = model.PtrString(formatAmount(acc.cash))
+	summary.UsableCash = model.PtrString(formatAmount(acc.cash))
+	summary.TotalMarketValue = model.PtrString(formatAmount(marketValue))
+	summary.TotalCost = model.PtrString(formatAmount(cost))
+	summary.UnrealizedProfitLoss = model.PtrString(formatAmount(unrealized))
+	return &summary, nil
+}
+
+// PlaceOrderV5 places an order through the live order API surface, see Broker.
+func (s *PaperSimulator) PlaceOrderV5(accountID int64, input model.PostStockOrderRequest) (*model.PostOrderResponse, error) {
+	return s.PlacePaperOrder(accountID, input)
+}
+
+// CancelOrderV5 cancels an order through the live order API surface, see Broker.
+func (s *PaperSimulator) CancelOrderV5(accountID int64, orderId int64) (bool, error) {
+	if _, err := s.CancelPaperOrder(accountID, strconv.FormatInt(orderId, 10)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetOrdersV5 lists orders through the live order API surface, see Broker.
+func (s *PaperSimulator) GetOrdersV5(accountID int64, status model.OrderStatus, stTime time.Time, endTime time.Time, count int32) ([]*model.OrderItemV5, error) {
+	orders, err := s.GetPaperOrders(accountID, status, stTime, 0)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]*model.OrderItemV5, 0, len(orders))
+	for _, o := range orders {
+		if endTime.Year() > 2000 && len(o.Items) > 0 && o.Items[0].GetCreateTime0() > endTime.UnixMilli() {
+			continue
+		}
+		rs = append(rs, o)
+		if count > 0 && len(rs) >= int(count) {
+			break
+		}
+	}
+	return rs, nil
+}
+
+// GetRealtimeStockQuote reports the latest quote for `tickerID` in the shape of
+// the quotes API.
+func (s *PaperSimulator) GetRealtimeStockQuote(tickerID int64) (*model.GetStockQuoteResponse, error) {
+	q, ok := s.Quote(tickerID)
+	if !ok {
+		return nil, fmt.Errorf("no quote for ticker %d", tickerID)
+	}
+	response := model.GetStockQuoteResponse{
+		TickerId: model.PtrInt64(tickerID),
+		Close:    model.PtrString(formatAmount(q.Last)),
+	}
+	return &response, nil
+}
+
+// Quote returns the latest quote known for `tickerID`.
+func (s *PaperSimulator) Quote(tickerID int64) (SimQuote, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, ok := s.quotes[tickerID]
+	return q, ok
+}
+
+// UpdateQuote merges `q` into the known quote for `tickerID` and matches the
+// working orders on it. Day orders created before the quote's date expire.
+func (s *PaperSimulator) UpdateQuote(tickerID int64, q SimQuote) {
+	s.mu.Lock()
+	prev := s.quotes[tickerID]
+	if q.Time.IsZero() {
+		q.Time = prev.Time
+	}
+	if q.Last == 0 {
+		q.Last = prev.Last
+	}
+	if q.Bid == 0 {
+		q.Bid = prev.Bid
+	}
+	if q.Ask == 0 {
+		q.Ask = prev.Ask
+	}
+	s.quotes[tickerID] = q
+	if q.Time.After(s.now) {
+		s.now = q.Time
+	}
+
+	var fills []SimFill
+	for _, acc := range s.accounts {
+		if p, ok := acc.positions[tickerID]; ok && q.Last > 0 {
+			p.LastPrice = q.Last
+		}
+		for _, o := range acc.orders {
+			if o.status != simStatusWorking || o.input.GetTickerId() != tickerID {
+				continue
+			}
+			if isDayExpired(o, q.Time) {
+				o.status = simStatusExpired
+				continue
+			}
+			fills = append(fills, s.match(o)...)
+		}
+	}
+	// High and Low only hold for this update
+	q.High, q.Low = 0, 0
+	s.quotes[tickerID] = q
+	s.mu.Unlock()
+
+	s.notify(fills)
+}
+
+// HandleQuote feeds a Type102Message, Type103Message or Type104Message, or its
+// decoded JSON, into UpdateQuote. Other messages are ignored.
+func (s *PaperSimulator) HandleQuote(message interface{}) error {
+	if m, ok := message.(map[string]interface{}); ok {
+		b, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		switch {
+		case m["deal"] != nil:
+			message = &Type103Message{}
+		case m["bidList"] != nil || m["askList"] != nil:
+			message = &Type104Message{}
+		case m["close"] != nil:
+			message = &Type102Message{}
+		default:
+			return nil
+		}
+		if err = json.Unmarshal(b, message); err != nil {
+			return err
+		}
+	}
+
+	switch m := message.(type) {
+	case Type102Message:
+		return s.HandleQuote(&m)
+	case Type103Message:
+		return s.HandleQuote(&m)
+	case Type104Message:
+		return s.HandleQuote(&m)
+	case *Type102Message:
+		last, err := parseFloat("close", &m.Close)
+		if err != nil {
+			return fmt.Errorf("quote for ticker %d: %s", m.TickerID, err.Error())
+		}
+		s.UpdateQuote(int64(m.TickerID), SimQuote{Time: stampTime(m.TradeStamp), Last: last})
+	case *Type103Message:
+		last, err := parseFloat("deal price", &m.Deal.Price)
+		if err != nil {
+			return fmt.Errorf("deal for ticker %d: %s", m.TickerID, err.Error())
+		}
+		s.UpdateQuote(int64(m.TickerID), SimQuote{Time: stampTime(m.TradeStamp), Last: last})
+	case *Type104Message:
+		var (
+			q   SimQuote
+			err error
+		)
+		if len(m.BidList) > 0 {
+			if q.Bid, err = parseFloat("bid price", &m.BidList[0].Price); err != nil {
+				return fmt.Errorf("book for ticker %d: %s", m.TickerID, err.Error())
+			}
+		}
+		if len(m.AskList) > 0 {
+			if q.Ask, err = parseFloat("ask price", &m.AskList[0].Price); err != nil {
+				return fmt.Errorf("book for ticker %d: %s", m.TickerID, err.Error())
+			}
+		}
+		s.UpdateQuote(int64(m.TickerID), q)
+	}
+	return nil
+}
+
+// HandleMessage lets the simulator be registered as a websocket callback.
+func (s *PaperSimulator) HandleMessage(ctx context.Context, topic Topic, message interface{}) error {
+	return s.HandleQuote(message)
+}
+
+// match fills `o` if the latest quote allows it. Callers hold s.mu.
+func (s *PaperSimulator) match(o *simOrder) []SimFill {
+	q, ok := s.quotes[o.input.GetTickerId()]
+	if !ok || o.status != simStatusWorking {
+		return nil
+	}
+	price, ok := s.fillPrice(o, q)
+	if !ok {
+		return nil
+	}
+
+	acc := s.accounts[o.accountID]
+	qty := o.input.GetQuantity() - o.filledQty
+	buy := strings.EqualFold(string(o.input.GetAction()), string(model.BUY))
+	commission := math.Max(s.MinCommission, s.CommissionPerShare*qty)
+	if buy && qty*price+commission > acc.cash+1e-9 {
+		o.status = simStatusFailed
+		return nil
+	}
+	if !buy && qty > acc.held(o.input.GetTickerId())+1e-9 {
+		o.status = simStatusFailed
+		return nil
+	}
+
+	fill := SimFill{
+		AccountID:  o.accountID,
+		OrderID:    o.id,
+		TickerID:   o.input.GetTickerId(),
+		Action:     o.input.GetAction(),
+		Quantity:   qty,
+		Price:      price,
+		Commission: commission,
+		Time:       s.now,
+	}
+	o.avgPrice = (o.avgPrice*o.filledQty + price*qty) / (o.filledQty + qty)
+	o.filledQty += qty
+	o.filled = fill.Time
+	o.status = simStatusFilled
+
+	pos, ok := acc.positions[fill.TickerID]
+	if !ok {
+		pos = &SimPosition{TickerID: fill.TickerID}
+		acc.positions[fill.TickerID] = pos
+	}
+	signed := qty
+	if !buy {
+		signed = -qty
+	}
+	pos.apply(signed, price)
+	if q.Last > 0 {
+		pos.LastPrice = q.Last
+	} else {
+		pos.LastPrice = price
+	}
+	acc.cash -= signed*price + commission
+	acc.fills = append(acc.fills, fill)
+	return []SimFill{fill}
+}
+
+// fillPrice returns the price `o` fills at against `q`, if it fills.
+func (s *PaperSimulator) fillPrice(o *simOrder, q SimQuote) (float64, bool) {
+	buy := strings.EqualFold(string(o.input.GetAction()), string(model.BUY))
+	market := q.Last
+	if buy && q.Ask > 0 {
+		market = q.Ask
+	} else if !buy && q.Bid > 0 {
+		market = q.Bid
+	}
+	if market <= 0 {
+		return 0, false
+	}
+
+	orderType := strings.ToUpper(string(o.input.GetOrderType()))
+	if isStopOrder(o) && !o.triggered {
+		if buy {
+			o.triggered = market >= o.input.GetAuxPrice() || (q.High > 0 && q.High >= o.input.GetAuxPrice())
+		} else {
+			o.triggered = market <= o.input.GetAuxPrice() || (q.Low > 0 && q.Low <= o.input.GetAuxPrice())
+		}
+		if !o.triggered {
+			return 0, false
+		}
+		if orderType == string(model.STP) {
+			// a stop triggered intrabar fills at its stop, one gapped through fills at the market
+			if (buy && market < o.input.GetAuxPrice()) || (!buy && market > o.input.GetAuxPrice()) {
+				market = o.input.GetAuxPrice()
+			}
+		}
+	}
+
+	switch orderType {
+	case string(model.MKT), string(model.STP):
+		return s.slip(market, buy), true
+	}
+
+	limit := o.input.GetLmtPrice()
+	switch {
+	case buy && market <= limit:
+		return market, true
+	case !buy && market >= limit:
+		return market, true
+	case buy && q.Low > 0 && q.Low <= limit:
+		return limit, true
+	case !buy && q.High > 0 && q.High >= limit:
+		return limit, true
+	}
+	return 0, false
+}
+
+func (s *PaperSimulator) slip(price float64, buy bool) float64 {
+	if buy {
+		return price * (1 + s.SlippageBps/10000)
+	}
+	return price * (1 - s.SlippageBps/10000)
+}
+
+func (s *PaperSimulator) notify(fills []SimFill) {
+	if s.OnFill == nil {
+		return
+	}
+	for _, f := range fills {
+		s.OnFill(f)
+	}
+}
+
+// clock is the time of the latest quote, zero before the first one.
+func (s *PaperSimulator) clock() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// checkOrder rejects `input`, the new terms of `o` or a new order when `o` is
+// nil, before the first timed quote or when it would sell more shares than
+// are held and not already being sold. Callers hold s.mu.
+func (s *PaperSimulator) checkOrder(acc *simAccount, o *simOrder, input model.PostStockOrderRequest) error {
+	if s.now.IsZero() {
+		return fmt.Errorf("no quote received yet, the simulator clock has not started")
+	}
+	if strings.EqualFold(string(input.GetAction()), string(model.BUY)) {
+		return nil
+	}
+	var selling float64
+	for _, other := range acc.orders {
+		if other != o && other.status == simStatusWorking && other.input.GetTickerId() == input.GetTickerId() &&
+			!strings.EqualFold(string(other.input.GetAction()), string(model.BUY)) {
+			selling += other.input.GetQuantity() - other.filledQty
+		}
+	}
+	held := acc.held(input.GetTickerId())
+	if input.GetQuantity()+selling > held+1e-9 {
+		return fmt.Errorf("cash account cannot sell %v of ticker %d: %v held, %v in working sells", input.GetQuantity(), input.GetTickerId(), held, selling)
+	}
+	return nil
+}
+
+func (s *PaperSimulator) account(accountID int64) (*simAccount, error) {
+	acc, ok := s.accounts[accountID]
+	if !ok {
+		return nil, fmt.Errorf("paper account %d not found", accountID)
+	}
+	return acc, nil
+}
+
+func (s *PaperSimulator) workingOrder(accountID int64, orderID string) (*simOrder, error) {
+	acc, err := s.account(accountID)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range acc.orders {
+		if o.id == orderID {
+			if o.status != simStatusWorking {
+				return nil, fmt.Errorf("order %s is %s", orderID, strings.ToLower(o.status))
+			}
+			return o, nil
+		}
+	}
+	return nil, fmt.Errorf("order %s not found", orderID)
+}
+
+// held is the quantity of `tickerID` in the account.
+func (a *simAccount) held(tickerID int64) float64 {
+	if p, ok := a.positions[tickerID]; ok {
+		return p.Quantity
+	}
+	return 0
+}
+
+func (a *simAccount) openPositions() []SimPosition {
+	out := make([]SimPosition, 0, len(a.positions))
+	for _, p := range a.positions {
+		if p.Quantity != 0 {
+			out = append(out, *p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].TickerID < out[j].TickerID })
+	return out
+}
+
+// apply adds a signed fill of `qty` at `price`, at average cost.
+func (p *SimPosition) apply(qty, price float64) {
+	switch {
+	case p.Quantity == 0 || (p.Quantity > 0) == (qty > 0):
+		p.AvgCost = (math.Abs(p.Quantity)*p.AvgCost + math.Abs(qty)*price) / (math.Abs(p.Quantity) + math.Abs(qty))
+	default:
+		closed := math.Min(math.Abs(qty), math.Abs(p.Quantity))
+		if p.Quantity > 0 {
+			p.RealizedPnL += closed * (price - p.AvgCost)
+		} else {
+			p.RealizedPnL += closed * (p.AvgCost - price)
+		}
+		if math.Abs(qty) > math.Abs(p.Quantity) {
+			p.AvgCost = price
+		}
+	}
+	p.Quantity += qty
+	if p.Quantity == 0 {
+		p.AvgCost = 0
+	}
+}
+
+func (o *simOrder) toOrderItemV5() *model.OrderItemV5 {
+	var (
+		in        = o.input
+		working   = o.status == simStatusWorking
+		quantity  = model.PtrString(formatAmount(in.GetQuantity()))
+		filledQty = model.PtrString(formatAmount(o.filledQty))
+	)
+	item := model.OrderItemV5ItemsInner{
+		OrderId:                   model.PtrString(o.id),
+		TickerId:                  model.PtrInt64(in.GetTickerId()),
+		TickerType:                model.PtrString("stock"),
+		AssetType:                 model.PtrString("stock"),
+		Action:                    model.PtrString(string(in.GetAction())),
+		OrderType:                 model.PtrString(string(in.GetOrderType())),
+		TimeInForce:               model.PtrString(string(in.GetTimeInForce())),
+		TotalQuantity:             quantity,
+		FilledQuantity:            filledQty,
+		RemainQuantity:            model.PtrString(formatAmount(in.GetQuantity() - o.filledQty)),
+		StatusName:                model.PtrString(o.status),
+		CreateTime0:               model.PtrInt64(o.created.UnixMilli()),
+		CreateTime:                model.PtrString(o.created.Format(DefaultTokenExpiryFormat)),
+		CanCancel:                 model.PtrBool(working),
+		CanModify:                 model.PtrBool(working),
+		OutsideRegularTradingHour: in.OutsideRegularTradingHour,
+	}
+	ord := &model.OrderItemV5{
+		OrderId:                   model.PtrString(o.id),
+		ComboTickerType:           model.PtrString("stock"),
+		Action:                    model.PtrString(string(in.GetAction())),
+		OrderType:                 model.PtrString(string(in.GetOrderType())),
+		TimeInForce:               model.PtrString(string(in.GetTimeInForce())),
+		Status:                    model.PtrString(o.status),
+		StatusName:                model.PtrString(o.status),
+		Quantity:                  quantity,
+		FilledQuantity:            filledQty,
+		CanCancel:                 model.PtrBool(working),
+		CanModify:                 model.PtrBool(working),
+		OutsideRegularTradingHour: in.OutsideRegularTradingHour,
+	}
+	if in.GetLmtPrice() > 0 {
+		ord.LmtPrice = model.PtrString(formatAmount(in.GetLmtPrice()))
+	}
+	if in.GetAuxPrice() > 0 {
+		ord.AuxPrice = model.PtrString(formatAmount(in.GetAuxPrice()))
+	}
+	if o.filledQty > 0 {
+		amount := model.PtrString(formatAmount(o.avgPrice * o.filledQty))
+		item.AvgFilledPrice = model.PtrString(formatAmount(o.avgPrice))
+		item.FilledTime0 = model.PtrInt64(o.filled.UnixMilli())
+		item.FilledTime = model.PtrString(o.filled.Format(DefaultTokenExpiryFormat))
+		item.FilledAmount = amount
+		ord.FilledAmount = amount
+		ord.FilledTotalAmount = amount
+	}
+	ord.Items = []model.OrderItemV5ItemsInner{item}
+	return ord
+}
+
+func isStopOrder(o *simOrder) bool {
+	orderType := strings.ToUpper(string(o.input.GetOrderType()))
+	return orderType == string(model.STP) || orderType == "STP_LMT"
+}
+
+func isDayExpired(o *simOrder, now time.Time) bool {
+	if !strings.EqualFold(string(o.input.GetTimeInForce()), string(model.DAY)) || now.IsZero() {
+		return false
+	}
+	y1, m1, d1 := o.created.Date()
+	y2, m2, d2 := now.Date()
+	return y2 > y1 || (y2 == y1 && (m2 > m1 || (m2 == m1 && d2 > d1)))
+}
+
+// stampTime converts a millisecond trade stamp, zero when unknown.
+func stampTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+func simResponse(orderID string) *interface{} {
+	var response interface{} = map[string]interface{}{
+		"orderId": orderID,
+		"success": true,
+	}
+	return &response
+}
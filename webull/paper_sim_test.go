@@ -0,0 +1,181 @@
+package webull
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+func newTestMarketOrder(action model.OrderSide, qty float64, tickerID int64) model.PostStockOrderRequest {
+	input := newTestOrder(action, qty, 0, tickerID)
+	input.LmtPrice = nil
+	input.OrderType = model.PtrOrderType(model.MKT)
+	return input
+}
+
+func TestPaperSimulatorMarketOrder(t *testing.T) {
+	asrt := assert.New(t)
+	s := NewPaperSimulator(10000)
+	s.CommissionPerShare = 0.01
+	s.MinCommission = 1
+	s.SlippageBps = 10
+
+	var fills []SimFill
+	s.OnFill = func(f SimFill) { fills = append(fills, f) }
+
+	_, err := s.PlacePaperOrder(DefaultSimAccountID, newTestMarketOrder(model.BUY, 10, 1))
+	asrt.Error(err, "the clock starts with the first quote")
+
+	s.UpdateQuote(1, SimQuote{Time: time.Date(2023, 3, 1, 15, 0, 0, 0, time.UTC)})
+	placed, err := s.PlacePaperOrder(DefaultSimAccountID, newTestMarketOrder(model.BUY, 10, 1))
+	asrt.Empty(err)
+	asrt.NotEmpty(placed.GetOrderId())
+	asrt.Empty(fills, "no price yet")
+
+	asrt.Empty(s.HandleQuote(map[string]interface{}{
+		"tickerId": 1,
+		"bidList":  []interface{}{map[string]interface{}{"price": "99.9", "volume": "100"}},
+		"askList":  []interface{}{map[string]interface{}{"price": "100", "volume": "100"}},
+	}))
+	asrt.Len(fills, 1)
+	asrt.InDelta(100.1, fills[0].Price, 1e-9)
+	asrt.InDelta(1.0, fills[0].Commission, 1e-9)
+	asrt.InDelta(10000-1001-1, s.Cash(DefaultSimAccountID), 1e-6)
+
+	positions := s.Positions(DefaultSimAccountID)
+	asrt.Len(positions, 1)
+	asrt.Equal(10.0, positions[0].Quantity)
+	asrt.InDelta(100.1, positions[0].AvgCost, 1e-9)
+
+	orders, err := s.GetPaperOrders(DefaultSimAccountID, model.ALL, time.Time{}, 10)
+	asrt.Empty(err)
+	asrt.Len(orders, 1)
+	asrt.Equal(simStatusFilled, orders[0].GetStatus())
+	asrt.Equal(10.0, testFloat(asrt, orders[0].Items[0].FilledQuantity))
+
+	// sell the position back at the bid
+	_, err = s.PlacePaperOrder(DefaultSimAccountID, newTestMarketOrder(model.SELL, 10, 1))
+	asrt.Empty(err)
+	asrt.Len(fills, 2)
+	asrt.Empty(s.Positions(DefaultSimAccountID))
+
+	// cash accounts cannot go short
+	_, err = s.PlacePaperOrder(DefaultSimAccountID, newTestMarketOrder(model.SELL, 1, 1))
+	asrt.Error(err)
+	asrt.Len(fills, 2)
+}
+
+func TestPaperSimulatorLimitOrder(t *testing.T) {
+	asrt := assert.New(t)
+	s := NewPaperSimulator(10000)
+	s.UpdateQuote(1, SimQuote{Time: time.Date(2023, 3, 1, 15, 0, 0, 0, time.UTC), Last: 50})
+
+	placed, err := s.PlacePaperOrder(DefaultSimAccountID, newTestOrder(model.BUY, 10, 45, 1))
+	asrt.Empty(err)
+	working, err := s.GetPaperOrders(DefaultSimAccountID, model.WORKING, time.Time{}, 10)
+	asrt.Empty(err)
+	asrt.Len(working, 1)
+
+	// a bar trading through the limit fills at the limit
+	s.UpdateQuote(1, SimQuote{Last: 47, High: 51, Low: 44})
+	asrt.Len(s.Fills(DefaultSimAccountID), 1)
+	asrt.Equal(45.0, s.Fills(DefaultSimAccountID)[0].Price)
+	asrt.Equal(placed.GetOrderId(), s.Fills(DefaultSimAccountID)[0].OrderID)
+
+	summary, err := s.GetPaperAccountSummary(DefaultSimAccountID)
+	asrt.Empty(err)
+	asrt.Len(summary.Positions, 1)
+	asrt.InDelta(10000-450+470, testFloat(asrt, summary.NetLiquidation), 1e-6)
+	asrt.InDelta(10000-450, testFloat(asrt, summary.UsableCash), 1e-6)
+
+	// the 10 shares held can be sold once, not twice
+	_, err = s.PlacePaperOrder(DefaultSimAccountID, newTestOrder(model.SELL, 10, 60, 1))
+	asrt.Empty(err)
+	_, err = s.PlacePaperOrder(DefaultSimAccountID, newTestOrder(model.SELL, 1, 60, 1))
+	asrt.Error(err)
+	asrt.InDelta(10020.0, s.NetLiquidation(DefaultSimAccountID), 1e-6)
+}
+
+func TestPaperSimulatorCancelModify(t *testing.T) {
+	asrt := assert.New(t)
+	s := NewPaperSimulator(1000)
+	s.UpdateQuote(1, SimQuote{Time: time.Date(2023, 3, 1, 15, 0, 0, 0, time.UTC), Last: 50})
+
+	placed, err := s.PlacePaperOrder(DefaultSimAccountID, newTestOrder(model.BUY, 10, 40, 1))
+	asrt.Empty(err)
+	id := placed.GetOrderId()
+
+	_, err = s.ModifyPaperOrder(DefaultSimAccountID, id, newTestOrder(model.BUY, 10, 50, 1))
+	asrt.Empty(err)
+	asrt.Len(s.Fills(DefaultSimAccountID), 1)
+
+	_, err = s.CancelPaperOrder(DefaultSimAccountID, id)
+	asrt.Error(err, "filled orders cannot be cancelled")
+
+	placed, err = s.PlacePaperOrder(DefaultSimAccountID, newTestOrder(model.BUY, 1, 10, 1))
+	asrt.Empty(err)
+	_, err = s.CancelPaperOrder(DefaultSimAccountID, placed.GetOrderId())
+	asrt.Empty(err)
+	cancelled, err := s.GetPaperOrders(DefaultSimAccountID, model.OrderStatus(simStatusCancelled), time.Time{}, 10)
+	asrt.Empty(err)
+	asrt.Len(cancelled, 1)
+
+	// not enough cash left for another 11 shares
+	_, err = s.PlacePaperOrder(DefaultSimAccountID, newTestOrder(model.BUY, 11, 50, 1))
+	asrt.Empty(err)
+	failed, err := s.GetPaperOrders(DefaultSimAccountID, model.OrderStatus(simStatusFailed), time.Time{}, 10)
+	asrt.Empty(err)
+	asrt.Len(failed, 1)
+
+	_, err = s.PlacePaperOrder(42, newTestOrder(model.BUY, 1, 10, 1))
+	asrt.Error(err)
+}
+
+func TestPaperSimulatorStopAndExpiry(t *testing.T) {
+	asrt := assert.New(t)
+	s := NewPaperSimulator(10000)
+	day := time.Date(2023, 3, 1, 15, 0, 0, 0, time.UTC)
+	s.UpdateQuote(1, SimQuote{Time: day, Last: 100})
+
+	stop := newTestMarketOrder(model.SELL, 5, 1)
+	stop.OrderType = model.PtrOrderType(model.STP)
+	stop.AuxPrice = model.PtrFloat64(95)
+	o := &simOrder{input: stop, status: simStatusWorking}
+	_, ok := s.fillPrice(o, SimQuote{Last: 97})
+	asrt.False(ok)
+	price, ok := s.fillPrice(o, SimQuote{Last: 97, Low: 94})
+	asrt.True(ok)
+	asrt.Equal(95.0, price)
+	o = &simOrder{input: stop, status: simStatusWorking}
+	price, ok = s.fillPrice(o, SimQuote{Last: 90})
+	asrt.True(ok)
+	asrt.Equal(90.0, price, "gapped through the stop")
+
+	_, err := s.PlacePaperOrder(DefaultSimAccountID, newTestOrder(model.BUY, 1, 90, 1))
+	asrt.Empty(err)
+	s.UpdateQuote(1, SimQuote{Time: day.Add(24 * time.Hour), Last: 99})
+	expired, err := s.GetPaperOrders(DefaultSimAccountID, model.OrderStatus(simStatusExpired), time.Time{}, 10)
+	asrt.Empty(err)
+	asrt.Len(expired, 1)
+}
+
+func TestPaperSimulatorHandleMessage(t *testing.T) {
+	asrt := assert.New(t)
+	s := NewPaperSimulator(10000)
+
+	// messages arrive from the websocket decoded into maps
+	err := s.HandleMessage(context.Background(), Topic{Type: 103}, map[string]interface{}{
+		"tickerId":   913256135,
+		"tradeStamp": 1677682800000,
+		"deal":       map[string]interface{}{"price": "151.25", "volume": "100"},
+	})
+	asrt.Empty(err)
+	_, err = s.PlacePaperOrder(DefaultSimAccountID, newTestMarketOrder(model.BUY, 1, 913256135))
+	asrt.Empty(err)
+	fills := s.Fills(DefaultSimAccountID)
+	asrt.Len(fills, 1)
+	asrt.Equal(151.25, fills[0].Price)
+}
@@ -0,0 +1,320 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	model "quantfu.com/webull/openapi"
+)
+
+// CashFlow is money moving in (positive) or out (negative) of an account.
+type CashFlow struct {
+	Time   time.Time
+	Amount float64
+}
+
+// BenchmarkComparison is account performance relative to a benchmark.
+type BenchmarkComparison struct {
+	Return float64
+	// Excess is the account's time-weighted return minus the benchmark return.
+	Excess        float64
+	Beta          float64
+	Correlation   float64
+	TrackingError float64
+}
+
+// Performance is account performance over a date range, adjusted for deposits,
+// withdrawals and dividends.
+type Performance struct {
+	From       time.Time
+	To         time.Time
+	StartValue float64
+	EndValue   float64
+	// NetFlows is deposits minus withdrawals inside the range.
+	NetFlows  float64
+	Dividends float64
+	// Gain is the change in value not explained by deposits and withdrawals.
+	Gain float64
+	// TWR is the time-weighted return, which ignores the size and timing of flows.
+	TWR float64
+	// PriceReturn is the time-weighted return without dividend income.
+	PriceReturn float64
+	// MWR is the annualised money-weighted return (IRR) of the flows.
+	MWR float64
+	// MaxDrawdown is the largest peak to trough decline of the flow-adjusted index.
+	MaxDrawdown float64
+	// Volatility is the annualised standard deviation of the period returns.
+	Volatility float64
+	// Index is the growth of 1 invested at the start, adjusted for flows.
+	Index     []EquityPoint
+	Benchmark *BenchmarkComparison
+
+	returns []float64
+}
+
+// NetLiquidationSeries converts net liquidation points into an equity series,
+// sorted by date.
+func NetLiquidationSeries(points []model.NetLiqidationTrendInner) ([]EquityPoint, error) {
+	series := make([]EquityPoint, 0, len(points))
+	for _, p := range points {
+		var ts Timestamp
+		if err := ts.UnmarshalJSON([]byte(`"` + p.GetDate() + `"`)); err != nil || ts.IsZero() {
+			return nil, fmt.Errorf("invalid net liquidation date %q", p.GetDate())
+		}
+		value, err := parseFloat("netLiquidation", p.NetLiquidation)
+		if err != nil {
+			return nil, fmt.Errorf("net liquidation on %s: %s", p.GetDate(), err.Error())
+		}
+		series = append(series, EquityPoint{Time: ts.Time, Value: value})
+	}
+	sort.Slice(series, func(i, j int) bool { return series[i].Time.Before(series[j].Time) })
+	return series, nil
+}
+
+// ComputePerformance measures `series` between `from` and `to`, either of which
+// may be zero for an open range. `flows` are deposits and withdrawals and
+// `dividends` dividend income; each is booked at the end of the day it falls on.
+func ComputePerformance(series []EquityPoint, flows, dividends []CashFlow, from, to time.Time) (*Performance, error) {
+	points := make([]EquityPoint, 0, len(series))
+	for _, p := range series {
+		if (!from.IsZero() && p.Time.Before(from)) || (!to.IsZero() && p.Time.After(to)) {
+			continue
+		}
+		points = append(points, p)
+	}
+	if len(points) < 2 {
+		return nil, fmt.Errorf("need at least 2 points in range, got %d", len(points))
+	}
+
+	perf := &Performance{
+		From:       points[0].Time,
+		To:         points[len(points)-1].Time,
+		StartValue: points[0].Value,
+		EndValue:   points[len(points)-1].Value,
+		Index:      []EquityPoint{{Time: points[0].Time, Value: 1}},
+	}
+	var (
+		index, priceIndex = 1.0, 1.0
+		peak              = 1.0
+		irrFlows          = []CashFlow{{Time: perf.From, Amount: -perf.StartValue}}
+	)
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+		flow := sumFlows(flows, prev.Time, cur.Time)
+		dividend := sumFlows(dividends, prev.Time, cur.Time)
+		perf.NetFlows += flow
+		perf.Dividends += dividend
+		if flow != 0 {
+			irrFlows = append(irrFlows, CashFlow{Time: cur.Time, Amount: -flow})
+		}
+
+		var r, pr float64
+		if prev.Value != 0 {
+			r = (cur.Value-flow)/prev.Value - 1
+			pr = (cur.Value-flow-dividend)/prev.Value - 1
+		}
+		perf.returns = append(perf.returns, r)
+		index *= 1 + r
+		priceIndex *= 1 + pr
+		perf.Index = append(perf.Index, EquityPoint{Time: cur.Time, Value: index})
+		peak = math.Max(peak, index)
+		perf.MaxDrawdown = math.Max(perf.MaxDrawdown, (peak-index)/peak)
+	}
+	irrFlows = append(irrFlows, CashFlow{Time: perf.To, Amount: perf.EndValue})
+
+	perf.Gain = perf.EndValue - perf.StartValue - perf.NetFlows
+	perf.TWR = index - 1
+	perf.PriceReturn = priceIndex - 1
+	mwr, err := xirr(irrFlows)
+	if err != nil {
+		return nil, err
+	}
+	perf.MWR = mwr
+	perf.Volatility = stdDev(perf.returns) * math.Sqrt(DefaultPeriodsPerYear)
+	return perf, nil
+}
+
+// CompareBenchmark compares the performance with `benchmark` prices, such as
+// index closes, matched to each point by the latest benchmark price on or
+// before it.
+func (p *Performance) CompareBenchmark(benchmark []EquityPoint) (*BenchmarkComparison, error) {
+	sorted := append([]EquityPoint(nil), benchmark...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	aligned := make([]float64, len(p.Index))
+	for i, pt := range p.Index {
+		j := sort.Search(len(sorted), func(k int) bool { return sorted[k].Time.After(pt.Time) }) - 1
+		if j < 0 || sorted[j].Value <= 0 {
+			return nil, fmt.Errorf("no benchmark price on or before %s", pt.Time.Format("2006-01-02"))
+		}
+		aligned[i] = sorted[j].Value
+	}
+
+	cmp := &BenchmarkComparison{Return: aligned[len(aligned)-1]/aligned[0] - 1}
+	cmp.Excess = p.TWR - cmp.Return
+
+	bench := make([]float64, 0, len(p.returns))
+	for i := 1; i < len(aligned); i++ {
+		bench = append(bench, aligned[i]/aligned[i-1]-1)
+	}
+	active := make([]float64, len(bench))
+	for i := range bench {
+		active[i] = p.returns[i] - bench[i]
+	}
+	cov, varA, varB := covariance(p.returns, bench), covariance(p.returns, p.returns), covariance(bench, bench)
+	if varB > 0 {
+		cmp.Beta = cov / varB
+	}
+	if varA > 0 && varB > 0 {
+		cmp.Correlation = cov / math.Sqrt(varA*varB)
+	}
+	cmp.TrackingError = stdDev(active) * math.Sqrt(DefaultPeriodsPerYear)
+	p.Benchmark = cmp
+	return cmp, nil
+}
+
+// GetPerformance measures account `accountID` between `from` and `to`, adjusting
+// its net liquidation history for transfers and dividends.
+func (c *Client) GetPerformance(ctx context.Context, accountID int64, from, to time.Time) (*Performance, error) {
+	history, err := c.GetNetLiquidation(accountID, from)
+	if err != nil {
+		return nil, err
+	}
+	series, err := NetLiquidationSeries(*history)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := c.GetTransfersPager(accountID, transfersPageSize).All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dividends, err := c.GetDividendRecords(ctx, accountID, DividendFilter{From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+	flows, err := transferFlows(transfers)
+	if err != nil {
+		return nil, err
+	}
+	return ComputePerformance(series, flows, dividendFlows(dividends), from, to)
+}
+
+// GetPaperPerformance measures paper account `accountID` between `from` and `to`.
+func (c *Client) GetPaperPerformance(accountID int64, from, to time.Time) (*Performance, error) {
+	history, err := c.GetNetLiquidationPaper(accountID, from)
+	if err != nil {
+		return nil, err
+	}
+	series, err := NetLiquidationSeries(*history)
+	if err != nil {
+		return nil, err
+	}
+	return ComputePerformance(series, nil, nil, from, to)
+}
+
+// transferFlows converts completed transfers into cash flows. Pending transfers
+// have not moved money yet and are left out.
+func transferFlows(transfers []TransferRecord) ([]CashFlow, error) {
+	flows := make([]CashFlow, 0, len(transfers))
+	for _, t := range transfers {
+		status := strings.ToLower(t.Status + " " + t.StatusName)
+		if strings.Contains(status, "fail") || strings.Contains(status, "cancel") || strings.Contains(status, "reject") || strings.Contains(status, "pending") {
+			continue
+		}
+		amount := math.Abs(t.Amount.Float64())
+		switch t.TransferDirection() {
+		case TransferDeposit:
+		case TransferWithdrawal:
+			amount = -amount
+		default:
+			return nil, fmt.Errorf("transfer %s has unknown direction %q", t.ID, t.Direction)
+		}
+		flows = append(flows, CashFlow{Time: t.CreateTime.Time, Amount: amount})
+	}
+	return flows, nil
+}
+
+// dividendFlows converts dividend records into cash flows of their net amounts.
+func dividendFlows(records []DividendRecord) []CashFlow {
+	flows := make([]CashFlow, 0, len(records))
+	for _, r := range records {
+		flows = append(flows, CashFlow{Time: r.PayDate, Amount: r.Net})
+	}
+	return flows
+}
+
+// sumFlows adds the flows booked after the day of `after` up to the end of the
+// day of `until`.
+func sumFlows(flows []CashFlow, after, until time.Time) float64 {
+	var total float64
+	for _, f := range flows {
+		day := f.Time.Truncate(24 * time.Hour)
+		if day.After(after.Truncate(24*time.Hour)) && !day.After(until.Truncate(24*time.Hour)) {
+			total += f.Amount
+		}
+	}
+	return total
+}
+
+// xirr solves for the annualised rate at which `flows` have zero present value.
+func xirr(flows []CashFlow) (float64, error) {
+	if len(flows) < 2 {
+		return 0, nil
+	}
+	t0 := flows[0].Time
+	npv := func(rate float64) float64 {
+		var total float64
+		for _, f := range flows {
+			years := f.Time.Sub(t0).Hours() / 24 / 365
+			total += f.Amount / math.Pow(1+rate, years)
+		}
+		return total
+	}
+	// annualising a short range can give huge rates, so the upper bound grows
+	// until it brackets the root
+	lo, hi := -1+1e-9, 1.0
+	for npv(lo)*npv(hi) > 0 {
+		if hi > 1e300 {
+			return 0, fmt.Errorf("no money-weighted return solves the cash flows")
+		}
+		hi *= 10
+	}
+	// bisection is slow but cannot diverge
+	for i := 0; i < 2000 && hi-lo > 1e-12*math.Max(1, math.Abs(lo)); i++ {
+		mid := (lo + hi) / 2
+		if npv(lo)*npv(mid) <= 0 {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return (lo + hi) / 2, nil
+}
+
+func stdDev(values []float64) float64 {
+	return math.Sqrt(covariance(values, values))
+}
+
+// covariance is the sample covariance of two equal length series.
+func covariance(a, b []float64) float64 {
+	n := len(a)
+	if n < 2 || len(b) != n {
+		return 0
+	}
+	var meanA, meanB float64
+	for i := range a {
+		meanA += a[i]
+		meanB += b[i]
+	}
+	meanA /= float64(n)
+	meanB /= float64(n)
+	var total float64
+	for i := range a {
+		total += (a[i] - meanA) * (b[i] - meanB)
+	}
+	return total / float64(n-1)
+}
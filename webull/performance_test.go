@@ -0,0 +1,122 @@
+package webull
+
+import (
+	"context"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+func TestComputePerformance(t *testing.T) {
+	asrt := assert.New(t)
+	history := []model.NetLiqidationTrendInner{
+		{Date: model.PtrString("2023-01-04"), NetLiquidation: model.PtrString("2100")},
+		{Date: model.PtrString("2023-01-02"), NetLiquidation: model.PtrString("1000")},
+		{Date: model.PtrString("2023-01-03"), NetLiquidation: model.PtrString("1100")},
+		{Date: model.PtrString("2023-01-05"), NetLiquidation: model.PtrString("1890")},
+	}
+	series, err := NetLiquidationSeries(history)
+	asrt.Empty(err)
+	asrt.Equal(1000.0, series[0].Value)
+
+	day := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	flows := []CashFlow{{Time: day.AddDate(0, 0, 2).Add(10 * time.Hour), Amount: 1000}}
+	dividends := []CashFlow{{Time: day.AddDate(0, 0, 1), Amount: 10}}
+	perf, err := ComputePerformance(series, flows, dividends, time.Time{}, time.Time{})
+	asrt.Empty(err)
+
+	// +10%, 0% after the deposit, then -10%
+	asrt.InDelta(1.1*1.0*0.9-1, perf.TWR, 1e-9)
+	asrt.InDelta(1.09*1.0*0.9-1, perf.PriceReturn, 1e-9)
+	asrt.Equal(1000.0, perf.NetFlows)
+	asrt.Equal(10.0, perf.Dividends)
+	asrt.InDelta(-110.0, perf.Gain, 1e-9)
+	asrt.InDelta(0.1, perf.MaxDrawdown, 1e-9)
+	asrt.True(perf.MWR < 0)
+	asrt.True(perf.Volatility > 0)
+	asrt.Len(perf.Index, 4)
+
+	ranged, err := ComputePerformance(series, flows, dividends, day, day.AddDate(0, 0, 1))
+	asrt.Empty(err)
+	asrt.InDelta(0.1, ranged.TWR, 1e-9)
+	_, err = ComputePerformance(series, nil, nil, day.AddDate(0, 0, 3), time.Time{})
+	asrt.Error(err)
+
+	cmp, err := perf.CompareBenchmark([]EquityPoint{
+		{Time: day.AddDate(0, 0, -1), Value: 100},
+		{Time: day.AddDate(0, 0, 1), Value: 110},
+		{Time: day.AddDate(0, 0, 3), Value: 99},
+	})
+	asrt.Empty(err)
+	asrt.InDelta(-0.01, cmp.Return, 1e-9)
+	asrt.InDelta(perf.TWR+0.01, cmp.Excess, 1e-9)
+	asrt.InDelta(1.0, cmp.Correlation, 1e-9)
+	_, err = perf.CompareBenchmark([]EquityPoint{{Time: day.AddDate(0, 0, 1), Value: 100}})
+	asrt.Error(err)
+}
+
+func TestXIRR(t *testing.T) {
+	asrt := assert.New(t)
+	day := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	rate, err := xirr([]CashFlow{{Time: day, Amount: -1000}, {Time: day.AddDate(0, 0, 365), Amount: 1100}})
+	asrt.Empty(err)
+	asrt.InDelta(0.1, rate, 1e-6)
+	// a 10% gain in a week annualises far beyond 1e6
+	rate, err = xirr([]CashFlow{{Time: day, Amount: -1000}, {Time: day.AddDate(0, 0, 7), Amount: 1100}})
+	asrt.Empty(err)
+	asrt.InDelta(math.Pow(1.1, 365.0/7)-1, rate, 1e-6*rate)
+	_, err = xirr([]CashFlow{{Time: day, Amount: 1}, {Time: day.AddDate(1, 0, 0), Amount: 1}})
+	asrt.Error(err)
+}
+
+func TestTransferAndDividendFlows(t *testing.T) {
+	asrt := assert.New(t)
+	flows, err := transferFlows([]TransferRecord{
+		{Direction: "IN", Amount: 500, Status: "Completed"},
+		{Direction: "OUT", Amount: 200, Status: "Completed"},
+		{Direction: "IN", Amount: 900, Status: "Failed"},
+		{Direction: "IN", Amount: 300, Status: "PENDING"},
+	})
+	asrt.Empty(err)
+	asrt.Len(flows, 2)
+	asrt.Equal(-200.0, flows[1].Amount)
+	_, err = transferFlows([]TransferRecord{{ID: "4", Amount: 100, Status: "Completed"}})
+	asrt.Error(err)
+
+	divs := dividendFlows([]DividendRecord{{PayDate: time.Date(2023, 2, 16, 0, 0, 0, 0, time.UTC), Gross: 2.5, Net: 2.3}})
+	asrt.Len(divs, 1)
+	asrt.Equal(2.3, divs[0].Amount)
+}
+
+func TestGetPerformance(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	err = c.Login(Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	err = c.TradeLogin(Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		AccountType: model.AccountType(2),
+		TradePIN:    os.Getenv("WEBULL_PIN"),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	accID, err := c.GetAccountID()
+	asrt.Empty(err)
+	perf, err := c.GetPerformance(context.Background(), accID, time.Now().AddDate(0, -3, 0), time.Time{})
+	asrt.Empty(err)
+	asrt.NotNil(perf)
+}
@@ -0,0 +1,379 @@
+package webull
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	model "quantfu.com/webull/openapi"
+)
+
+// AccountKind classifies an account in a Portfolio.
+type AccountKind string
+
+// Account kinds
+const (
+	AccountKindMargin AccountKind = "MARGIN"
+	AccountKindCash   AccountKind = "CASH"
+	AccountKindIRA    AccountKind = "IRA"
+	AccountKindPaper  AccountKind = "PAPER"
+)
+
+// PortfolioPosition is a holding in a single account.
+type PortfolioPosition struct {
+	TickerID      int64
+	Symbol        string
+	AssetType     string
+	Quantity      decimal.Decimal
+	AvgCost       decimal.Decimal
+	CostBasis     decimal.Decimal
+	LastPrice     decimal.Decimal
+	MarketValue   decimal.Decimal
+	UnrealizedPnL decimal.Decimal
+}
+
+// AccountSnapshot holds the balances, buying powers and positions of one account.
+type AccountSnapshot struct {
+	AccountID int64
+	Kind      AccountKind
+
+	NetLiquidation       decimal.Decimal
+	TotalMarketValue     decimal.Decimal
+	CashBalance          decimal.Decimal
+	UsableCash           decimal.Decimal
+	DayBuyingPower       decimal.Decimal
+	OvernightBuyingPower decimal.Decimal
+	OptionBuyingPower    decimal.Decimal
+	CryptoBuyingPower    decimal.Decimal
+	TotalCost            decimal.Decimal
+	UnrealizedPnL        decimal.Decimal
+
+	Positions []PortfolioPosition
+}
+
+// PortfolioTotals sums the balances of a set of accounts.
+type PortfolioTotals struct {
+	NetLiquidation   decimal.Decimal
+	TotalMarketValue decimal.Decimal
+	CashBalance      decimal.Decimal
+	UnrealizedPnL    decimal.Decimal
+}
+
+// Portfolio is a snapshot of every account. The embedded totals cover the
+// brokerage accounts only; paper accounts are kept apart in Paper and
+// PaperTotals so simulated money never adds to real money.
+type Portfolio struct {
+	Time     time.Time
+	Accounts []AccountSnapshot
+	Paper    []AccountSnapshot
+
+	PortfolioTotals
+	PaperTotals PortfolioTotals
+}
+
+// GetPortfolio takes a snapshot of every brokerage account, and of the paper
+// accounts when `includePaper` is set.
+func (c *Client) GetPortfolio(includePaper bool) (*Portfolio, error) {
+	accounts, err := c.GetAccountsV5()
+	if err != nil {
+		return nil, err
+	}
+	home, err := c.GetAccountV5()
+	if err != nil {
+		return nil, err
+	}
+	p := &Portfolio{Time: time.Now()}
+	for _, acc := range accounts.AccountList {
+		summary, err := accountSummaryV5(home, acc.GetSecAccountId())
+		if err != nil {
+			return nil, err
+		}
+		snap, err := newAccountSnapshot(summary)
+		if err != nil {
+			return nil, fmt.Errorf("account %d: %s", acc.GetSecAccountId(), err.Error())
+		}
+		if kind := accountKind(acc.GetAccountTypeName(), acc.GetAccountType()); kind != "" {
+			snap.Kind = kind
+		}
+		p.add(snap)
+	}
+	if includePaper {
+		ids, err := c.GetPaperTradeAccountIDs()
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			snap, err := c.GetPaperAccountSnapshot(id)
+			if err != nil {
+				return nil, err
+			}
+			p.add(*snap)
+		}
+	}
+	return p, nil
+}
+
+// GetAccountSnapshot takes a snapshot of brokerage account `accountID`.
+func (c *Client) GetAccountSnapshot(accountID int64) (*AccountSnapshot, error) {
+	home, err := c.GetAccountV5()
+	if err != nil {
+		return nil, err
+	}
+	summary, err := accountSummaryV5(home, accountID)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := newAccountSnapshot(summary)
+	if err != nil {
+		return nil, fmt.Errorf("account %d: %s", accountID, err.Error())
+	}
+	return &snap, nil
+}
+
+// GetPaperAccountSnapshot takes a snapshot of paper account `accountID`.
+func (c *Client) GetPaperAccountSnapshot(accountID int64) (*AccountSnapshot, error) {
+	res, err := c.GetPaperAccountSummary(accountID)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := newPaperAccountSnapshot(res)
+	if err != nil {
+		return nil, fmt.Errorf("paper account %d: %s", accountID, err.Error())
+	}
+	snap.AccountID = accountID
+	return &snap, nil
+}
+
+// accountSummaryV5 picks the summary of `accountID` out of the V5 home response.
+func accountSummaryV5(home *model.GetAccountsResponseV5, accountID int64) (model.AccountSummaryV5, error) {
+	for _, summary := range home.AccountSummaryList {
+		if summary.GetSecAccountId() == accountID {
+			return summary, nil
+		}
+	}
+	return model.AccountSummaryV5{}, fmt.Errorf("account %d not found", accountID)
+}
+
+// Account returns the snapshot of brokerage or paper account `accountID`, if present.
+func (p *Portfolio) Account(accountID int64) (AccountSnapshot, bool) {
+	for _, acc := range append(p.Accounts, p.Paper...) {
+		if acc.AccountID == accountID {
+			return acc, true
+		}
+	}
+	return AccountSnapshot{}, false
+}
+
+// Positions returns the holdings of every brokerage account, merged by ticker.
+func (p *Portfolio) Positions() []PortfolioPosition {
+	return mergePositions(p.Accounts)
+}
+
+// PaperPositions returns the holdings of every paper account, merged by ticker.
+func (p *Portfolio) PaperPositions() []PortfolioPosition {
+	return mergePositions(p.Paper)
+}
+
+func mergePositions(accounts []AccountSnapshot) []PortfolioPosition {
+	var (
+		out   = make([]PortfolioPosition, 0)
+		index = make(map[int64]int)
+	)
+	for _, acc := range accounts {
+		for _, pos := range acc.Positions {
+			i, ok := index[pos.TickerID]
+			if !ok {
+				index[pos.TickerID] = len(out)
+				out = append(out, pos)
+				continue
+			}
+			merged := &out[i]
+			merged.Quantity = merged.Quantity.Add(pos.Quantity)
+			merged.CostBasis = merged.CostBasis.Add(pos.CostBasis)
+			merged.MarketValue = merged.MarketValue.Add(pos.MarketValue)
+			merged.UnrealizedPnL = merged.UnrealizedPnL.Add(pos.UnrealizedPnL)
+			if !merged.Quantity.IsZero() {
+				merged.AvgCost = merged.CostBasis.Div(merged.Quantity)
+			}
+		}
+	}
+	return out
+}
+
+func (p *Portfolio) add(snap AccountSnapshot) {
+	totals := &p.PortfolioTotals
+	if snap.Kind == AccountKindPaper {
+		p.Paper = append(p.Paper, snap)
+		totals = &p.PaperTotals
+	} else {
+		p.Accounts = append(p.Accounts, snap)
+	}
+	totals.NetLiquidation = totals.NetLiquidation.Add(snap.NetLiquidation)
+	totals.TotalMarketValue = totals.TotalMarketValue.Add(snap.TotalMarketValue)
+	totals.CashBalance = totals.CashBalance.Add(snap.CashBalance)
+	totals.UnrealizedPnL = totals.UnrealizedPnL.Add(snap.UnrealizedPnL)
+}
+
+// newAccountSnapshot reads a V5 account summary. Balances other than the net
+// liquidation, market value, cost and P&L are listed as `accountMembers`.
+func newAccountSnapshot(summary model.AccountSummaryV5) (AccountSnapshot, error) {
+	var p amountParser
+	members := make(map[string]decimal.Decimal, len(summary.AccountMembers))
+	for _, m := range summary.AccountMembers {
+		members[m.GetKey()] = p.optional(m.GetKey(), m.Value)
+	}
+	snap := AccountSnapshot{
+		AccountID:            summary.GetSecAccountId(),
+		Kind:                 accountKind(summary.GetAccountType()),
+		NetLiquidation:       p.optional("netLiquidation", summary.NetLiquidation),
+		TotalMarketValue:     p.optional("totalMarketValue", summary.TotalMarketValue),
+		CashBalance:          members[Field_CashBalance],
+		UsableCash:           members[Field_UsableCash],
+		DayBuyingPower:       members[Field_DayBuyingPower],
+		OvernightBuyingPower: members[Field_OvernightBuyingPower],
+		OptionBuyingPower:    members[Field_OptionBuyingPower],
+		CryptoBuyingPower:    members[Field_CryptoBuyingPower],
+		TotalCost:            p.optional("totalCost", summary.TotalCost),
+		UnrealizedPnL:        p.optional("unrealizedProfitLoss", summary.UnrealizedProfitLoss),
+		Positions:            make([]PortfolioPosition, 0, len(summary.Positions)),
+	}
+	if snap.TotalMarketValue.IsZero() {
+		snap.TotalMarketValue = members[Field_TotalMarketValue]
+	}
+	for _, pos := range summary.Positions {
+		snap.Positions = append(snap.Positions, newPortfolioPosition(PortfolioPosition{
+			AssetType:     pos.GetAssetType(),
+			Quantity:      p.optional("position", pos.Position),
+			AvgCost:       p.optional("costPrice", pos.CostPrice),
+			CostBasis:     p.optional("totalCost", pos.TotalCost),
+			LastPrice:     p.optional("lastPrice", pos.LastPrice),
+			MarketValue:   p.optional("marketValue", pos.MarketValue),
+			UnrealizedPnL: p.optional("unrealizedProfitLoss", pos.UnrealizedProfitLoss),
+		}, pos.Ticker))
+	}
+	if p.err != nil {
+		return snap, p.err
+	}
+	snap.fill()
+	return snap, nil
+}
+
+// newPaperAccountSnapshot reads a paper account summary.
+func newPaperAccountSnapshot(summary *model.PaperAccountSummary) (AccountSnapshot, error) {
+	var p amountParser
+	snap := AccountSnapshot{
+		Kind:             AccountKindPaper,
+		NetLiquidation:   p.optional("netLiquidation", summary.NetLiquidation),
+		TotalMarketValue: p.optional("totalMarketValue", summary.TotalMarketValue),
+		CashBalance:      p.optional("totalCash", summary.TotalCash),
+		UsableCash:       p.optional("usableCash", summary.UsableCash),
+		TotalCost:        p.optional("totalCost", summary.TotalCost),
+		UnrealizedPnL:    p.optional("unrealizedProfitLoss", summary.UnrealizedProfitLoss),
+		Positions:        make([]PortfolioPosition, 0, len(summary.Positions)),
+	}
+	for _, pos := range summary.Positions {
+		snap.Positions = append(snap.Positions, newPortfolioPosition(PortfolioPosition{
+			AssetType:     pos.GetAssetType(),
+			Quantity:      p.optional("position", pos.Position),
+			AvgCost:       p.optional("costPrice", pos.CostPrice),
+			CostBasis:     p.optional("totalCost", pos.TotalCost),
+			LastPrice:     p.optional("lastPrice", pos.LastPrice),
+			MarketValue:   p.optional("marketValue", pos.MarketValue),
+			UnrealizedPnL: p.optional("unrealizedProfitLoss", pos.UnrealizedProfitLoss),
+		}, pos.Ticker))
+	}
+	if p.err != nil {
+		return snap, p.err
+	}
+	snap.fill()
+	return snap, nil
+}
+
+// fill derives the balances an account response left out.
+func (snap *AccountSnapshot) fill() {
+	if snap.CashBalance.IsZero() {
+		snap.CashBalance = snap.UsableCash
+	}
+	if snap.TotalMarketValue.IsZero() {
+		for _, pos := range snap.Positions {
+			snap.TotalMarketValue = snap.TotalMarketValue.Add(pos.MarketValue)
+		}
+	}
+	if snap.NetLiquidation.IsZero() {
+		snap.NetLiquidation = snap.CashBalance.Add(snap.TotalMarketValue)
+	}
+}
+
+// newPortfolioPosition completes `pos` with its ticker and the values derived
+// from its quantity and prices.
+func newPortfolioPosition(pos PortfolioPosition, ticker *model.Ticker) PortfolioPosition {
+	pos.TickerID = ticker.GetTickerId()
+	pos.Symbol = ticker.GetSymbol()
+	if pos.AssetType == "" {
+		pos.AssetType = ticker.GetTemplate()
+	}
+	if pos.CostBasis.IsZero() {
+		pos.CostBasis = pos.Quantity.Mul(pos.AvgCost)
+	}
+	if pos.MarketValue.IsZero() {
+		pos.MarketValue = pos.Quantity.Mul(pos.LastPrice)
+	}
+	if pos.UnrealizedPnL.IsZero() && !pos.MarketValue.IsZero() {
+		pos.UnrealizedPnL = pos.MarketValue.Sub(pos.CostBasis)
+	}
+	return pos
+}
+
+// parseDecimal parses the required amount `name` as Webull sends it, failing
+// when it is missing or not a number.
+func parseDecimal(name string, s *string) (decimal.Decimal, error) {
+	if s == nil || *s == "" {
+		return decimal.Zero, fmt.Errorf("%s is missing", name)
+	}
+	return parseOptionalDecimal(name, s)
+}
+
+// parseOptionalDecimal is parseDecimal for an amount that may be left out, which
+// reads as zero.
+func parseOptionalDecimal(name string, s *string) (decimal.Decimal, error) {
+	if s == nil || *s == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(strings.ReplaceAll(*s, ",", ""))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid %s %q", name, *s)
+	}
+	return d, nil
+}
+
+// amountParser parses a run of optional amounts, keeping the first error.
+type amountParser struct {
+	err error
+}
+
+func (p *amountParser) optional(name string, s *string) decimal.Decimal {
+	d, err := parseOptionalDecimal(name, s)
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+	return d
+}
+
+// accountKind classifies an account from its type names.
+func accountKind(names ...string) AccountKind {
+	for _, name := range names {
+		s := strings.ToUpper(name)
+		switch {
+		case strings.Contains(s, "IRA"):
+			return AccountKindIRA
+		case strings.Contains(s, "MARGIN"):
+			return AccountKindMargin
+		case strings.Contains(s, "CASH"):
+			return AccountKindCash
+		case strings.Contains(s, "PAPER"):
+			return AccountKindPaper
+		}
+	}
+	return ""
+}
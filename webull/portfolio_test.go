@@ -0,0 +1,107 @@
+package webull
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+// accountHomeV5 is a hand-written V5 home response for one margin account.
+const accountHomeV5 = `{"accountSummaryList":[{"secAccountId":12345,"accountType":"MARGIN",
+	"netLiquidation":"10500.50","totalCost":"4000","unrealizedProfitLoss":"500.5",
+	"accountMembers":[{"key":"totalMarketValue","value":"4500.50"},{"key":"cashBalance","value":"6000.00"},
+		{"key":"dayBuyingPower","value":"24000.00"},{"key":"overnightBuyingPower","value":"12000.00"},
+		{"key":"optionBuyingPower","value":"6000.00"},{"key":"cryptoBuyingPower","value":"0"}],
+	"positions":[{"ticker":{"tickerId":913256135,"symbol":"AAPL","template":"stock"},"position":"20",
+		"costPrice":"150","totalCost":"3000","lastPrice":"175.025","marketValue":"3500.50",
+		"unrealizedProfitLoss":"500.50","assetType":"stock"},
+		{"ticker":{"tickerId":913255598,"symbol":"SPY","template":"etf"},"position":"2.5",
+		"costPrice":"400","lastPrice":"400","assetType":"etf"}]}]}`
+
+func TestNewAccountSnapshot(t *testing.T) {
+	asrt := assert.New(t)
+	var home model.GetAccountsResponseV5
+	asrt.Empty(json.Unmarshal([]byte(accountHomeV5), &home))
+	summary, err := accountSummaryV5(&home, 12345)
+	asrt.Empty(err)
+	_, err = accountSummaryV5(&home, 1)
+	asrt.Error(err)
+
+	snap, err := newAccountSnapshot(summary)
+	asrt.Empty(err)
+	asrt.Equal(int64(12345), snap.AccountID)
+	asrt.Equal(AccountKindMargin, snap.Kind)
+	asrt.Equal("10500.5", snap.NetLiquidation.String())
+	asrt.Equal("4500.5", snap.TotalMarketValue.String())
+	asrt.Equal("6000", snap.CashBalance.String())
+	asrt.Equal("24000", snap.DayBuyingPower.String())
+	asrt.Equal("12000", snap.OvernightBuyingPower.String())
+	asrt.Len(snap.Positions, 2)
+	asrt.Equal("AAPL", snap.Positions[0].Symbol)
+	asrt.Equal("3000", snap.Positions[0].CostBasis.String())
+	asrt.Equal("500.5", snap.Positions[0].UnrealizedPnL.String())
+	asrt.Equal("1000", snap.Positions[1].CostBasis.String())
+	asrt.Equal("1000", snap.Positions[1].MarketValue.String())
+
+	p := &Portfolio{}
+	p.add(snap)
+	snap.AccountID, snap.Kind = 2, AccountKindIRA
+	p.add(snap)
+	asrt.Equal("21001", p.NetLiquidation.String())
+	merged := p.Positions()
+	asrt.Len(merged, 2)
+	asrt.Equal("40", merged[0].Quantity.String())
+	asrt.Equal("150", merged[0].AvgCost.String())
+	ira, ok := p.Account(2)
+	asrt.True(ok)
+	asrt.Equal(AccountKindIRA, ira.Kind)
+
+	// paper accounts stay out of the brokerage totals and positions
+	paper, err := newPaperAccountSnapshot(&model.PaperAccountSummary{
+		NetLiquidation: model.PtrString("1000000"),
+		TotalCash:      model.PtrString("999000"),
+		Positions: []model.PaperPosition{{
+			Ticker:    &model.Ticker{TickerId: model.PtrInt64(913256135), Symbol: model.PtrString("AAPL")},
+			Position:  model.PtrString("5"),
+			CostPrice: model.PtrString("200"),
+			LastPrice: model.PtrString("200"),
+		}},
+	})
+	asrt.Empty(err)
+	paper.AccountID = 3
+	p.add(paper)
+	asrt.Equal("21001", p.NetLiquidation.String())
+	asrt.Equal("1000000", p.PaperTotals.NetLiquidation.String())
+	asrt.Equal("40", p.Positions()[0].Quantity.String())
+	asrt.Len(p.PaperPositions(), 1)
+	asrt.Equal("1000", p.PaperPositions()[0].MarketValue.String())
+	got, ok := p.Account(3)
+	asrt.True(ok)
+	asrt.Equal(AccountKindPaper, got.Kind)
+
+	_, err = newPaperAccountSnapshot(&model.PaperAccountSummary{NetLiquidation: model.PtrString("n/a")})
+	asrt.Error(err)
+}
+
+func TestGetPortfolio(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	err = c.Login(Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	p, err := c.GetPortfolio(true)
+	asrt.Empty(err)
+	asrt.NotEmpty(p.Accounts)
+}
@@ -0,0 +1,74 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimiter spaces out requests to at most `rate` per second, allowing bursts
+// of up to `burst` requests. Attach it with Client.RateLimit.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    int
+	tokens   float64
+	last     time.Time
+}
+
+// NewRateLimiter is a constructor for a RateLimiter allowing `rate` requests per
+// second in bursts of up to `burst`. It panics unless `rate` is positive; leave
+// Client.RateLimit nil for no limit.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if !(rate > 0) {
+		panic(fmt.Sprintf("webull: non-positive rate %v for NewRateLimiter", rate))
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		interval: time.Duration(float64(time.Second) / rate),
+		burst:    burst,
+		tokens:   float64(burst),
+	}
+}
+
+// Wait blocks until a request may be sent or `ctx` is done.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := r.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available at `now`, otherwise it returns how
+// long until the next one is.
+func (r *RateLimiter) reserve(now time.Time) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.interval <= 0 {
+		return 0
+	}
+	if !r.last.IsZero() {
+		r.tokens += float64(now.Sub(r.last)) / float64(r.interval)
+		if r.tokens > float64(r.burst) {
+			r.tokens = float64(r.burst)
+		}
+	}
+	r.last = now
+	if r.tokens >= 1 {
+		r.tokens--
+		return 0
+	}
+	return time.Duration((1 - r.tokens) * float64(r.interval))
+}
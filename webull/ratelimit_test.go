@@ -0,0 +1,28 @@
+package webull
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	asrt := assert.New(t)
+	r := NewRateLimiter(10, 2)
+	now := time.Now()
+	asrt.Equal(time.Duration(0), r.reserve(now))
+	asrt.Equal(time.Duration(0), r.reserve(now))
+	asrt.Equal(100*time.Millisecond, r.reserve(now), "burst used up")
+	asrt.Equal(time.Duration(0), r.reserve(now.Add(100*time.Millisecond)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	slow := NewRateLimiter(0.001, 1)
+	asrt.Empty(slow.Wait(context.Background()))
+	asrt.Error(slow.Wait(ctx))
+
+	asrt.Panics(func() { NewRateLimiter(0, 1) })
+	asrt.Panics(func() { NewRateLimiter(-5, 1) })
+}
@@ -0,0 +1,657 @@
+package webull
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	model "quantfu.com/webull/openapi"
+)
+
+// Risk rules reported in RiskError.Rule
+const (
+	RiskRuleKillSwitch       = "kill_switch"
+	RiskRuleOrderNotional    = "max_order_notional"
+	RiskRulePosition         = "max_position"
+	RiskRuleDailyLoss        = "max_daily_loss"
+	RiskRuleOrderRate        = "max_orders_per_minute"
+	RiskRuleSymbolNotAllowed = "symbol_not_allowed"
+)
+
+// RiskError is returned when an order is blocked by the RiskManager before it
+// is sent.
+type RiskError struct {
+	Rule   string
+	Reason string
+}
+
+func (e *RiskError) Error() string {
+	return fmt.Sprintf("order blocked by %s: %s", e.Rule, e.Reason)
+}
+
+// RiskLimits configures pre-trade checks. Zero values disable a check.
+type RiskLimits struct {
+	// MaxOrderNotional caps quantity * price of a single order.
+	MaxOrderNotional float64
+	// MaxPositionPerSymbol caps the absolute projected position per ticker.
+	MaxPositionPerSymbol float64
+	// MaxDailyLoss caps the drop in net liquidation since the previous close.
+	MaxDailyLoss float64
+	// MaxOrdersPerMinute caps orders sent in any rolling minute.
+	MaxOrdersPerMinute int
+	// AllowTickerIDs, when not empty, is the only set of tickers that may be traded.
+	AllowTickerIDs []int64
+	// DenyTickerIDs may never be traded.
+	DenyTickerIDs []int64
+	// AllowSymbols and DenySymbols are resolved to ticker IDs on first use.
+	AllowSymbols []string
+	DenySymbols  []string
+}
+
+// RiskManager enforces RiskLimits on every order placed through a Client and
+// provides a kill switch. Attach it with Client.Risk.
+type RiskManager struct {
+	Limits RiskLimits
+
+	// PriceSource overrides how reference prices for notional checks are read.
+	// By default the limit price is used, falling back to the real-time quote.
+	PriceSource func(tickerID int64) (float64, error)
+	// PositionSource overrides how current positions are read. By default the
+	// manager tracks the fills reported to OnOrderEvent. Orders still working
+	// are added to either.
+	PositionSource func(account TradingAccount, tickerID int64) (float64, error)
+	// NetLiquidationSource overrides how current net liquidation is read. By
+	// default it is read from the account snapshot.
+	NetLiquidationSource func(account TradingAccount) (float64, error)
+	// PreviousCloseSource overrides how the net liquidation the daily loss is
+	// measured from is read. By default it is the last point of the net
+	// liquidation trend dated before today.
+	PreviousCloseSource func(account TradingAccount) (float64, error)
+
+	client *Client
+
+	mu           sync.Mutex
+	killed       bool
+	sent         []time.Time
+	accounts     map[TradingAccount]bool
+	filled       map[TradingAccount]map[int64]float64
+	open         map[*riskOrder]bool
+	openByID     map[string]*riskOrder
+	openByCombo  map[string][]*riskOrder
+	allow, deny  map[int64]bool
+	symbolsReady bool
+}
+
+// riskOrder is the signed quantity of an order that is still working, or of a
+// change to one that has not been accepted yet.
+type riskOrder struct {
+	account  TradingAccount
+	tickerID int64
+	open     float64
+	// id is the broker order ID, or the order a modification applies to
+	id     string
+	modify bool
+	// combo is the combo ID of an OTOCO or combo leg whose own order ID is
+	// not known until an order event reports it
+	combo string
+}
+
+// riskReservation holds the rate slots and exposure of orders that passed the
+// RiskManager until the broker accepts them (commit) or they fail (release).
+type riskReservation struct {
+	r      *RiskManager
+	sent   time.Time
+	orders []*riskOrder
+	done   bool
+}
+
+// NewRiskManager is a constructor for a RiskManager enforcing `limits` on `c`.
+// It sets c.Risk so every order placed through `c` is checked.
+func NewRiskManager(c *Client, limits RiskLimits) *RiskManager {
+	r := &RiskManager{
+		Limits:      limits,
+		client:      c,
+		accounts:    make(map[TradingAccount]bool),
+		filled:      make(map[TradingAccount]map[int64]float64),
+		open:        make(map[*riskOrder]bool),
+		openByID:    make(map[string]*riskOrder),
+		openByCombo: make(map[string][]*riskOrder),
+	}
+	c.Risk = r
+	return r
+}
+
+// Kill engages the kill switch, blocking all new orders, then cancels working
+// orders on `accounts`, or on every account the manager has seen if none are
+// given. The switch stays engaged even when there is no account to cancel on,
+// which is reported as an error.
+func (r *RiskManager) Kill(ctx context.Context, accounts ...TradingAccount) ([]CancelResult, error) {
+	r.mu.Lock()
+	r.killed = true
+	if len(accounts) == 0 {
+		for acc := range r.accounts {
+			accounts = append(accounts, acc)
+		}
+	}
+	r.mu.Unlock()
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("kill switch engaged, but no accounts are known to cancel orders on")
+	}
+
+	var (
+		all      = make([]CancelResult, 0)
+		firstErr error
+	)
+	for _, acc := range accounts {
+		results, err := r.client.CancelAll(ctx, acc, CancelFilter{})
+		all = append(all, results...)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return all, firstErr
+}
+
+// Reset disengages the kill switch.
+func (r *RiskManager) Reset() {
+	r.mu.Lock()
+	r.killed = false
+	r.mu.Unlock()
+}
+
+// Killed reports whether the kill switch is engaged.
+func (r *RiskManager) Killed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.killed
+}
+
+// Check runs every configured check against `input` without reserving
+// anything. Orders placed through the Client are checked and reserved in one
+// step, so concurrent orders cannot all pass the same limit.
+func (r *RiskManager) Check(account TradingAccount, input model.PostStockOrderRequest) error {
+	res, err := r.reserve(account, "", input)
+	if err != nil {
+		return err
+	}
+	res.release()
+	return nil
+}
+
+// checkKill blocks every order while the kill switch is engaged.
+func (r *RiskManager) checkKill() error {
+	if r.Killed() {
+		return &RiskError{Rule: RiskRuleKillSwitch, Reason: "kill switch engaged"}
+	}
+	return nil
+}
+
+// reserve checks `inputs` and, if they pass, reserves their rate slots and
+// exposure until the reservation is committed or released. A non-empty
+// `modifyID` checks `inputs` as the new terms of that working order, so only
+// the change in quantity counts as new exposure.
+func (r *RiskManager) reserve(account TradingAccount, modifyID string, inputs ...model.PostStockOrderRequest) (*riskReservation, error) {
+	if err := r.checkKill(); err != nil {
+		return nil, err
+	}
+	// checks that call out to the broker run before taking the lock
+	sources := make(map[int64]float64)
+	for _, input := range inputs {
+		if err := r.checkSymbol(input.GetTickerId()); err != nil {
+			return nil, err
+		}
+		if err := r.checkNotional(input); err != nil {
+			return nil, err
+		}
+		if _, ok := sources[input.GetTickerId()]; ok || r.PositionSource == nil || r.Limits.MaxPositionPerSymbol <= 0 {
+			continue
+		}
+		current, err := r.PositionSource(account, input.GetTickerId())
+		if err != nil {
+			return nil, err
+		}
+		sources[input.GetTickerId()] = current
+	}
+	if err := r.checkDailyLoss(account); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.killed {
+		return nil, &RiskError{Rule: RiskRuleKillSwitch, Reason: "kill switch engaged"}
+	}
+	now := time.Now()
+	if err := r.checkRate(now, len(inputs)); err != nil {
+		return nil, err
+	}
+	res := &riskReservation{r: r, sent: now}
+	deltas := make(map[int64]float64)
+	for _, input := range inputs {
+		o := &riskOrder{account: account, tickerID: input.GetTickerId(), open: signedQuantity(input)}
+		if modifyID != "" {
+			o.id, o.modify = modifyID, true
+			if prev, ok := r.openByID[modifyID]; ok {
+				o.open -= prev.open
+			}
+		}
+		deltas[o.tickerID] += o.open
+		res.orders = append(res.orders, o)
+	}
+	if r.Limits.MaxPositionPerSymbol > 0 {
+		for tickerID, delta := range deltas {
+			current, ok := sources[tickerID]
+			if !ok {
+				current = r.filled[account][tickerID]
+			}
+			projected := current + r.openQuantity(account, tickerID) + delta
+			if math.Abs(projected) > r.Limits.MaxPositionPerSymbol && delta != 0 {
+				return nil, &RiskError{Rule: RiskRulePosition, Reason: fmt.Sprintf("position %.2f in ticker %d exceeds %.2f", projected, tickerID, r.Limits.MaxPositionPerSymbol)}
+			}
+		}
+	}
+	for _, o := range res.orders {
+		r.sent = append(r.sent, now)
+		r.open[o] = true
+	}
+	r.accounts[account] = true
+	return res, nil
+}
+
+// openQuantity sums the working and reserved quantity in `tickerID`. The
+// caller holds r.mu.
+func (r *RiskManager) openQuantity(account TradingAccount, tickerID int64) float64 {
+	var open float64
+	for o := range r.open {
+		if o.account == account && o.tickerID == tickerID {
+			open += o.open
+		}
+	}
+	return open
+}
+
+// commit keeps the reservation once the broker accepted the orders, tracking
+// each by the matching ID in `orderIDs`.
+func (res *riskReservation) commit(orderIDs ...string) {
+	if res == nil || res.done {
+		return
+	}
+	r := res.r
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res.done = true
+	for i, o := range res.orders {
+		if o.modify {
+			delete(r.open, o)
+			if prev, ok := r.openByID[o.id]; ok {
+				prev.open += o.open
+			} else {
+				o.modify = false
+				r.open[o] = true
+				r.openByID[o.id] = o
+			}
+			continue
+		}
+		if i < len(orderIDs) && orderIDs[i] != "" {
+			o.id = orderIDs[i]
+			r.openByID[o.id] = o
+		}
+	}
+}
+
+// commitCombo keeps the reservation of OTOCO or combo legs accepted as combo
+// `comboID`. The response carries no ID per leg, so each leg is tracked by the
+// combo until an order event names its order.
+func (res *riskReservation) commitCombo(comboID string) {
+	if res == nil || res.done {
+		return
+	}
+	r := res.r
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res.done = true
+	for _, o := range res.orders {
+		o.combo = comboID
+	}
+	r.openByCombo[comboID] = append(r.openByCombo[comboID], res.orders...)
+}
+
+// release gives back the rate slots and exposure of orders that were not sent
+// or not accepted. It does nothing once the reservation was committed.
+func (res *riskReservation) release() {
+	if res == nil || res.done {
+		return
+	}
+	r := res.r
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res.done = true
+	for _, o := range res.orders {
+		delete(r.open, o)
+		for i, t := range r.sent {
+			if t.Equal(res.sent) {
+				r.sent = append(r.sent[:i], r.sent[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// OnOrderEvent updates positions and working orders from an OrderTracker
+// event. Set it as (or call it from) OrderTracker.OnEvent.
+func (r *RiskManager) OnOrderEvent(e OrderEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	o, ok := r.openByID[e.OrderID]
+	if !ok {
+		if o = r.comboLeg(e); o == nil {
+			return
+		}
+	}
+	if e.FillQuantity > 0 {
+		fill := e.FillQuantity
+		if o.open < 0 {
+			fill = -fill
+		}
+		if _, ok := r.filled[o.account]; !ok {
+			r.filled[o.account] = make(map[int64]float64)
+		}
+		r.filled[o.account][o.tickerID] += fill
+		if math.Abs(fill) >= math.Abs(o.open) {
+			o.open = 0
+		} else {
+			o.open -= fill
+		}
+	}
+	if e.Terminal {
+		r.closeOrder(e.OrderID)
+	}
+}
+
+// comboLeg binds the order of event `e` to the first unbound leg of its combo
+// with the same ticker and side, returning nil if there is none. The caller
+// holds r.mu.
+func (r *RiskManager) comboLeg(e OrderEvent) *riskOrder {
+	if e.Order == nil || e.Order.GetComboId() == "" || len(e.Order.Items) == 0 {
+		return nil
+	}
+	var (
+		comboID  = e.Order.GetComboId()
+		tickerID = e.Order.Items[0].GetTickerId()
+		sell     = strings.EqualFold(e.Order.GetAction(), string(model.SELL))
+		legs     = r.openByCombo[comboID]
+	)
+	for i, o := range legs {
+		if o.tickerID != tickerID || (o.open < 0) != sell {
+			continue
+		}
+		o.id = e.OrderID
+		r.openByID[o.id] = o
+		r.openByCombo[comboID] = append(legs[:i:i], legs[i+1:]...)
+		if len(r.openByCombo[comboID]) == 0 {
+			delete(r.openByCombo, comboID)
+		}
+		return o
+	}
+	return nil
+}
+
+// orderCancelled stops counting order `orderID` as working once a cancel was
+// accepted. Fills reported for it afterwards still count.
+func (r *RiskManager) orderCancelled(orderID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if o, ok := r.openByID[orderID]; ok {
+		o.open = 0
+	}
+}
+
+// closeOrder forgets order `orderID`. The caller holds r.mu.
+func (r *RiskManager) closeOrder(orderID string) {
+	if o, ok := r.openByID[orderID]; ok {
+		delete(r.open, o)
+		delete(r.openByID, orderID)
+	}
+}
+
+func (r *RiskManager) checkSymbol(tickerID int64) error {
+	lim := r.Limits
+	if len(lim.AllowTickerIDs)+len(lim.DenyTickerIDs)+len(lim.AllowSymbols)+len(lim.DenySymbols) == 0 {
+		return nil
+	}
+	if err := r.resolveSymbols(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.deny[tickerID] {
+		return &RiskError{Rule: RiskRuleSymbolNotAllowed, Reason: fmt.Sprintf("ticker %d is denied", tickerID)}
+	}
+	if len(r.allow) > 0 && !r.allow[tickerID] {
+		return &RiskError{Rule: RiskRuleSymbolNotAllowed, Reason: fmt.Sprintf("ticker %d is not allowed", tickerID)}
+	}
+	return nil
+}
+
+// resolveSymbols builds the allow and deny sets once.
+func (r *RiskManager) resolveSymbols() error {
+	r.mu.Lock()
+	ready := r.symbolsReady
+	r.mu.Unlock()
+	if ready {
+		return nil
+	}
+	allow := make(map[int64]bool)
+	deny := make(map[int64]bool)
+	for _, id := range r.Limits.AllowTickerIDs {
+		allow[id] = true
+	}
+	for _, id := range r.Limits.DenyTickerIDs {
+		deny[id] = true
+	}
+	for _, sym := range r.Limits.AllowSymbols {
+		id, err := r.client.GetTickerID(strings.ToUpper(sym))
+		if err != nil {
+			return fmt.Errorf("unable to resolve allowed symbol %s: %s", sym, err.Error())
+		}
+		allow[id] = true
+	}
+	for _, sym := range r.Limits.DenySymbols {
+		id, err := r.client.GetTickerID(strings.ToUpper(sym))
+		if err != nil {
+			return fmt.Errorf("unable to resolve denied symbol %s: %s", sym, err.Error())
+		}
+		deny[id] = true
+	}
+	r.mu.Lock()
+	r.allow, r.deny, r.symbolsReady = allow, deny, true
+	r.mu.Unlock()
+	return nil
+}
+
+// checkRate checks that `n` more orders fit in the rolling minute ending at
+// `now`. The caller holds r.mu.
+func (r *RiskManager) checkRate(now time.Time, n int) error {
+	if r.Limits.MaxOrdersPerMinute <= 0 {
+		return nil
+	}
+	cutoff := now.Add(-time.Minute)
+	kept := r.sent[:0]
+	for _, t := range r.sent {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	r.sent = kept
+	if len(r.sent)+n > r.Limits.MaxOrdersPerMinute {
+		return &RiskError{Rule: RiskRuleOrderRate, Reason: fmt.Sprintf("%d orders sent in the last minute", len(r.sent))}
+	}
+	return nil
+}
+
+func (r *RiskManager) checkNotional(input model.PostStockOrderRequest) error {
+	if r.Limits.MaxOrderNotional <= 0 {
+		return nil
+	}
+	price := input.GetLmtPrice()
+	if price <= 0 {
+		var err error
+		if price, err = r.referencePrice(input.GetTickerId()); err != nil {
+			return err
+		}
+	}
+	notional := math.Abs(input.GetQuantity()) * price
+	if notional > r.Limits.MaxOrderNotional {
+		return &RiskError{Rule: RiskRuleOrderNotional, Reason: fmt.Sprintf("notional %.2f exceeds %.2f", notional, r.Limits.MaxOrderNotional)}
+	}
+	return nil
+}
+
+func (r *RiskManager) referencePrice(tickerID int64) (float64, error) {
+	if r.PriceSource != nil {
+		return r.PriceSource(tickerID)
+	}
+	quote, err := r.client.GetRealtimeStockQuote(tickerID)
+	if err != nil {
+		return 0, fmt.Errorf("unable to price ticker %d: %s", tickerID, err.Error())
+	}
+	price, err := parseFloat("close", quote.Close)
+	if err != nil {
+		return 0, fmt.Errorf("unable to price ticker %d: %s", tickerID, err.Error())
+	}
+	if price <= 0 {
+		return 0, fmt.Errorf("no reference price for ticker %d", tickerID)
+	}
+	return price, nil
+}
+
+// checkDailyLoss compares current net liquidation with the previous close, so
+// losses taken before the first order of the day or before a restart count.
+func (r *RiskManager) checkDailyLoss(account TradingAccount) error {
+	if r.Limits.MaxDailyLoss <= 0 {
+		return nil
+	}
+	start, err := r.previousClose(account)
+	if err != nil {
+		return err
+	}
+	current, err := r.netLiquidation(account)
+	if err != nil {
+		return err
+	}
+	if loss := start - current; loss > r.Limits.MaxDailyLoss {
+		return &RiskError{Rule: RiskRuleDailyLoss, Reason: fmt.Sprintf("daily loss %.2f exceeds %.2f", loss, r.Limits.MaxDailyLoss)}
+	}
+	return nil
+}
+
+func (r *RiskManager) netLiquidation(account TradingAccount) (float64, error) {
+	if r.NetLiquidationSource != nil {
+		return r.NetLiquidationSource(account)
+	}
+	var (
+		snap *AccountSnapshot
+		err  error
+	)
+	if account.Paper {
+		snap, err = r.client.GetPaperAccountSnapshot(account.ID)
+	} else {
+		snap, err = r.client.GetAccountSnapshot(account.ID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to read net liquidation: %s", err.Error())
+	}
+	return snap.NetLiquidation.InexactFloat64(), nil
+}
+
+// previousClose reads the last net liquidation of the trend dated before
+// today. An account without an earlier point is measured from its first one.
+func (r *RiskManager) previousClose(account TradingAccount) (float64, error) {
+	if r.PreviousCloseSource != nil {
+		return r.PreviousCloseSource(account)
+	}
+	var (
+		points *[]model.NetLiqidationTrendInner
+		err    error
+		since  = time.Now().AddDate(0, 0, -7)
+	)
+	if account.Paper {
+		points, err = r.client.GetNetLiquidationPaper(account.ID, since)
+	} else {
+		points, err = r.client.GetNetLiquidation(account.ID, since)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to read net liquidation trend: %s", err.Error())
+	}
+	if points == nil || len(*points) == 0 {
+		return 0, fmt.Errorf("no net liquidation trend for account %d", account.ID)
+	}
+	series, err := NetLiquidationSeries(*points)
+	if err != nil {
+		return 0, err
+	}
+	return previousClose(series, time.Now()), nil
+}
+
+// previousClose is the value of the last point of `series`, oldest first,
+// dated before the day of `now`, or of the first point if there is none.
+func previousClose(series []EquityPoint, now time.Time) float64 {
+	today := now.Format("2006-01-02")
+	start := series[0].Value
+	for _, p := range series {
+		if p.Time.Format("2006-01-02") < today {
+			start = p.Value
+		}
+	}
+	return start
+}
+
+// signedQuantity is positive for buys and negative for sells.
+func signedQuantity(input model.PostStockOrderRequest) float64 {
+	qty := math.Abs(input.GetQuantity())
+	if strings.EqualFold(string(input.GetAction()), string(model.SELL)) {
+		return -qty
+	}
+	return qty
+}
+
+// reserveRisk checks `inputs` with c.Risk, if one is attached, and reserves
+// them until the broker answers. The returned reservation may be nil.
+func (c *Client) reserveRisk(account TradingAccount, inputs ...model.PostStockOrderRequest) (*riskReservation, error) {
+	if c.Risk == nil {
+		return nil, nil
+	}
+	return c.Risk.reserve(account, "", inputs...)
+}
+
+// riskAccount parses the string account ID taken by the older order methods.
+// It is only needed, so can only fail, when c.Risk is attached.
+func (c *Client) riskAccount(accountID string) (TradingAccount, error) {
+	if c.Risk == nil {
+		return TradingAccount{}, nil
+	}
+	id, err := strconv.ParseInt(accountID, 10, 64)
+	if err != nil {
+		return TradingAccount{}, fmt.Errorf("invalid account ID %q", accountID)
+	}
+	return TradingAccount{ID: id}, nil
+}
+
+// reserveRiskModify is reserveRisk for new terms of working order `orderID`.
+func (c *Client) reserveRiskModify(account TradingAccount, orderID string, input model.PostStockOrderRequest) (*riskReservation, error) {
+	if c.Risk == nil {
+		return nil, nil
+	}
+	return c.Risk.reserve(account, orderID, input)
+}
+
+// riskCancelled tells c.Risk, if one is attached, that a cancel was accepted.
+func (c *Client) riskCancelled(orderID string) {
+	if c.Risk != nil {
+		c.Risk.orderCancelled(orderID)
+	}
+}
@@ -0,0 +1,209 @@
+package webull
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+func newTestOrder(action model.OrderSide, qty, price float64, tickerID int64) model.PostStockOrderRequest {
+	return model.PostStockOrderRequest{
+		Action:      model.PtrOrderSide(action),
+		LmtPrice:    model.PtrFloat64(price),
+		OrderType:   model.PtrOrderType(model.LMT),
+		Quantity:    model.PtrFloat64(qty),
+		TickerId:    model.PtrInt64(tickerID),
+		TimeInForce: model.PtrTif(model.DAY),
+	}
+}
+
+func TestRiskManagerLimits(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+
+	netLiq := 10000.0
+	r := NewRiskManager(c, RiskLimits{
+		MaxOrderNotional:     1000,
+		MaxPositionPerSymbol: 10,
+		MaxDailyLoss:         500,
+		MaxOrdersPerMinute:   3,
+		DenyTickerIDs:        []int64{2},
+	})
+	r.NetLiquidationSource = func(TradingAccount) (float64, error) { return netLiq, nil }
+	r.PreviousCloseSource = func(TradingAccount) (float64, error) { return 10000, nil }
+	asrt.Equal(r, c.Risk)
+
+	acc := TradingAccount{ID: 1}
+	asrt.Empty(r.Check(acc, newTestOrder(model.BUY, 5, 100, 1)))
+
+	err = r.Check(acc, newTestOrder(model.BUY, 20, 100, 1))
+	asrt.Equal(RiskRuleOrderNotional, err.(*RiskError).Rule)
+
+	err = r.Check(acc, newTestOrder(model.BUY, 1, 10, 2))
+	asrt.Equal(RiskRuleSymbolNotAllowed, err.(*RiskError).Rule)
+
+	res, err := r.reserve(acc, "", newTestOrder(model.BUY, 8, 100, 1))
+	asrt.Empty(err)
+	res.commit("1")
+	err = r.Check(acc, newTestOrder(model.BUY, 5, 10, 1))
+	asrt.Equal(RiskRulePosition, err.(*RiskError).Rule)
+	asrt.Empty(r.Check(acc, newTestOrder(model.SELL, 5, 10, 1)))
+
+	netLiq = 9000
+	err = r.Check(acc, newTestOrder(model.SELL, 1, 10, 1))
+	asrt.Equal(RiskRuleDailyLoss, err.(*RiskError).Rule)
+	netLiq = 10000
+
+	for i := 0; i < 2; i++ {
+		res, err = r.reserve(acc, "", newTestOrder(model.SELL, 1, 10, 1))
+		asrt.Empty(err)
+		res.commit()
+	}
+	err = r.Check(acc, newTestOrder(model.SELL, 1, 10, 1))
+	asrt.Equal(RiskRuleOrderRate, err.(*RiskError).Rule)
+}
+
+func TestRiskManagerReservations(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	r := NewRiskManager(c, RiskLimits{MaxPositionPerSymbol: 10, MaxOrdersPerMinute: 100})
+	acc := TradingAccount{ID: 1}
+
+	// concurrent orders cannot all pass the same position limit
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		passed int
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.reserve(acc, "", newTestOrder(model.BUY, 3, 1, 1)); err == nil {
+				mu.Lock()
+				passed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	asrt.Equal(3, passed)
+
+	// a released reservation gives its exposure and rate slot back
+	r = NewRiskManager(c, RiskLimits{MaxPositionPerSymbol: 10, MaxOrdersPerMinute: 1})
+	res, err := r.reserve(acc, "", newTestOrder(model.BUY, 8, 1, 1))
+	asrt.Empty(err)
+	res.release()
+	res, err = r.reserve(acc, "", newTestOrder(model.BUY, 8, 1, 1))
+	asrt.Empty(err)
+	res.commit("7")
+	res.release()
+	_, err = r.reserve(acc, "", newTestOrder(model.BUY, 1, 1, 2))
+	asrt.Equal(RiskRuleOrderRate, err.(*RiskError).Rule)
+
+	r.Limits.MaxOrdersPerMinute = 0
+	// modifying the working order only counts the change in quantity
+	res, err = r.reserve(acc, "7", newTestOrder(model.BUY, 10, 1, 1))
+	asrt.Empty(err)
+	res.commit()
+	_, err = r.reserve(acc, "7", newTestOrder(model.BUY, 11, 1, 1))
+	asrt.Equal(RiskRulePosition, err.(*RiskError).Rule)
+
+	// fills move quantity from the working order into the position
+	r.OnOrderEvent(OrderEvent{OrderID: "7", FillQuantity: 4})
+	asrt.Equal(4.0, r.filled[acc][1])
+	asrt.Equal(10.0, r.openQuantity(acc, 1)+r.filled[acc][1])
+
+	// a cancelled order stops counting, its fills stay
+	r.orderCancelled("7")
+	r.OnOrderEvent(OrderEvent{OrderID: "7", Status: "Cancelled", Terminal: true})
+	asrt.Equal(0.0, r.openQuantity(acc, 1))
+	asrt.Empty(r.Check(acc, newTestOrder(model.BUY, 6, 1, 1)))
+	err = r.Check(acc, newTestOrder(model.BUY, 7, 1, 1))
+	asrt.Equal(RiskRulePosition, err.(*RiskError).Rule)
+}
+
+func TestRiskManagerKillSwitch(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	r := NewRiskManager(c, RiskLimits{})
+
+	results, err := r.Kill(context.Background())
+	asrt.Error(err, "no account has been seen to cancel on")
+	asrt.Empty(results)
+	asrt.True(r.Killed())
+
+	_, err = c.PlaceOrderV5(1, newTestOrder(model.BUY, 1, 1, 1))
+	asrt.Equal(RiskRuleKillSwitch, err.(*RiskError).Rule)
+	_, err = c.PlacePaperOrder(1, newTestOrder(model.BUY, 1, 1, 1))
+	asrt.Equal(RiskRuleKillSwitch, err.(*RiskError).Rule)
+
+	r.Reset()
+	asrt.False(r.Killed())
+}
+
+func TestRiskManagerPreviousClose(t *testing.T) {
+	asrt := assert.New(t)
+	series, err := NetLiquidationSeries([]model.NetLiqidationTrendInner{
+		{Date: model.PtrString("2023-03-02"), NetLiquidation: model.PtrString("10500")},
+		{Date: model.PtrString("2023-03-01"), NetLiquidation: model.PtrString("10000")},
+		{Date: model.PtrString("2023-03-03"), NetLiquidation: model.PtrString("9800")},
+	})
+	asrt.Empty(err)
+	// losses before the first check of the day are measured from yesterday's close
+	asrt.Equal(10500.0, previousClose(series, time.Date(2023, 3, 3, 15, 0, 0, 0, time.UTC)))
+	asrt.Equal(9800.0, previousClose(series, time.Date(2023, 3, 6, 9, 0, 0, 0, time.UTC)))
+	asrt.Equal(10000.0, previousClose(series, time.Date(2023, 3, 1, 9, 0, 0, 0, time.UTC)))
+}
+
+func TestRiskManagerComboLegs(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	r := NewRiskManager(c, RiskLimits{MaxPositionPerSymbol: 100})
+	acc := TradingAccount{ID: 1}
+
+	res, err := r.reserve(acc, "", newTestOrder(model.BUY, 10, 1, 1), newTestOrder(model.SELL, 10, 2, 1))
+	asrt.Empty(err)
+	res.commitCombo("c1")
+	asrt.Len(r.openByCombo["c1"], 2)
+
+	leg := func(orderID, action string) OrderEvent {
+		return OrderEvent{OrderID: orderID, Status: "Cancelled", Terminal: true, Order: &model.OrderItemV5{
+			OrderId: model.PtrString(orderID),
+			ComboId: model.PtrString("c1"),
+			Action:  model.PtrString(action),
+			Items:   []model.OrderItemV5ItemsInner{{TickerId: model.PtrInt64(1)}},
+		}}
+	}
+	// legs are bound to their orders by the events that name them and released
+	r.OnOrderEvent(leg("11", "SELL"))
+	asrt.Len(r.openByCombo["c1"], 1)
+	asrt.Equal(10.0, r.openQuantity(acc, 1))
+	r.OnOrderEvent(leg("12", "BUY"))
+	asrt.Empty(r.openByCombo)
+	asrt.Equal(0.0, r.openQuantity(acc, 1))
+	asrt.Empty(r.openByID)
+}
+
+func TestRiskAccount(t *testing.T) {
+	asrt := assert.New(t)
+	c, err := NewClient(nil)
+	asrt.Empty(err)
+	// without a RiskManager the account ID is passed through unparsed
+	_, err = c.riskAccount("not-a-number")
+	asrt.Empty(err)
+	NewRiskManager(c, RiskLimits{})
+	_, err = c.riskAccount("not-a-number")
+	asrt.Error(err)
+	acc, err := c.riskAccount("42")
+	asrt.Empty(err)
+	asrt.Equal(TradingAccount{ID: 42}, acc)
+}
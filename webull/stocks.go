@@ -1,13 +1,23 @@
 package webull
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 
 	model "quantfu.com/webull/openapi"
 )
 
+const (
+	// quotesBatchSize is the most tickers requested per batch quote call.
+	quotesBatchSize = 50
+	// maxConcurrentQuoteRequests bounds the batch quote calls in flight.
+	maxConcurrentQuoteRequests = 4
+)
+
 // GetTicker gets ticker information for a provided stock symbol
 func (c *Client) GetTicker(symbol string) (*model.LookupTickerResponse, error) {
 	var (
@@ -31,7 +41,9 @@ func (c *Client) GetTicker(symbol string) (*model.LookupTickerResponse, error) {
 	return &response, err
 }
 
-// GetTickerID is a helper function for getting a ticker ID from a stock symbol
+// GetTickerID is a helper function for getting a ticker ID from a stock symbol.
+// It prefers an exact US listing of `symbol` over the first search result; use
+// a SymbolResolver to pick the exchange and cache results.
 func (c *Client) GetTickerID(symbol string) (int64, error) {
 	res, err := c.GetTickerV5(symbol)
 	if err != nil {
@@ -40,10 +52,14 @@ func (c *Client) GetTickerID(symbol string) (int64, error) {
 	if len(res.Data) < 1 {
 		return 0, fmt.Errorf("No ticker found")
 	}
-	for _, symbolInfo := range res.Data {
-		return *symbolInfo.TickerId, nil
+	candidates := make([]TickerInfo, 0, len(res.Data))
+	for i := range res.Data {
+		candidates = append(candidates, searchTickerInfo(&res.Data[i]))
+	}
+	if info, ok := matchTicker(candidates, symbol, "", RegionUS); ok {
+		return info.ID, nil
 	}
-	return 0, nil
+	return *res.Data[0].TickerId, nil
 }
 
 // GetRealtimeStockQuote gets real-time data for ticker `tickerID`
@@ -64,6 +80,125 @@ func (c *Client) GetRealtimeStockQuote(tickerID int64) (*model.GetStockQuoteResp
 	return &response, err
 }
 
+// QuoteResult is the quote, or the error fetching it, of one ticker in a batch.
+type QuoteResult struct {
+	Quote *model.GetStockQuoteResponse
+	Err   error
+}
+
+// GetRealtimeStockQuotes gets real-time data for every ticker in `tickerIDs`,
+// fetching up to quotesBatchSize tickers per request and
+// maxConcurrentQuoteRequests requests at a time, paced by Client.RateLimit.
+// Failures are reported per ticker; the error is only set when no quote at all
+// could be fetched. Cancelling `ctx` aborts the requests in flight and those
+// not yet sent.
+func (c *Client) GetRealtimeStockQuotes(ctx context.Context, tickerIDs []int64) (map[int64]QuoteResult, error) {
+	var (
+		results = make(map[int64]QuoteResult, len(tickerIDs))
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, maxConcurrentQuoteRequests)
+		ids     = make([]int64, 0, len(tickerIDs))
+		seen    = make(map[int64]bool, len(tickerIDs))
+	)
+	for _, id := range tickerIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	var firstErr error
+batches:
+	for start := 0; start < len(ids); start += quotesBatchSize {
+		end := start + quotesBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		chunk := ids[start:end]
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			// the tickers of this and the following batches are never requested
+			mu.Lock()
+			for _, id := range ids[start:] {
+				results[id] = QuoteResult{Err: ctx.Err()}
+			}
+			if firstErr == nil {
+				firstErr = ctx.Err()
+			}
+			mu.Unlock()
+			break batches
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			quotes, err := c.getRealtimeStockQuotes(ctx, chunk)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			byID := make(map[int64]*model.GetStockQuoteResponse, len(quotes))
+			for i := range quotes {
+				byID[quotes[i].GetTickerId()] = &quotes[i]
+			}
+			for _, id := range chunk {
+				switch {
+				case err != nil:
+					results[id] = QuoteResult{Err: err}
+				case byID[id] == nil:
+					results[id] = QuoteResult{Err: fmt.Errorf("no quote returned for ticker %d", id)}
+				default:
+					results[id] = QuoteResult{Quote: byID[id]}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Err == nil {
+			return results, nil
+		}
+	}
+	if firstErr != nil {
+		return results, firstErr
+	}
+	return results, nil
+}
+
+// getRealtimeStockQuotes gets real-time data for a single batch of tickers.
+func (c *Client) getRealtimeStockQuotes(ctx context.Context, tickerIDs []int64) ([]model.GetStockQuoteResponse, error) {
+	var (
+		u, _        = url.Parse(BrokerQuotesGWEndpointV + "/bgw/quote/realtime")
+		response    []model.GetStockQuoteResponse
+		headersMap  = make(map[string]string)
+		queryParams = make(map[string]string)
+		ids         = make([]string, len(tickerIDs))
+	)
+
+	headersMap[HeaderKeyAccessToken] = c.AccessToken
+	headersMap[HeaderKeyDeviceID] = c.DeviceID
+
+	for i, id := range tickerIDs {
+		ids[i] = strconv.FormatInt(id, 10)
+	}
+	queryParams["ids"] = strings.Join(ids, ",")
+	queryParams["includeSecu"] = "1"
+	queryParams["delay"] = "0"
+	queryParams["more"] = "1"
+
+	err := c.GetAndDecodeWithContext(ctx, *u, &response, &headersMap, &queryParams)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 // GetStockFundamentals gets stock fundamentals for ticker `tickerID`
 func (c *Client) GetStockFundamentals(tickerID string) (*model.GetFundamentalsResponse, error) {
 	var (
@@ -106,9 +241,15 @@ func (c *Client) GetActiveGainersLosers(direction, regionID, userRegionID string
 
 // GetStockAnalysis gets Webull stock analysis for tickerID `tickerID`
 func (c *Client) GetStockAnalysis(tickerID string) (*model.GetStockAnalysisResponse, error) {
+	var response model.GetStockAnalysisResponse
+	err := c.getStockAnalysis(tickerID, &response)
+	return &response, err
+}
+
+// getStockAnalysis is GetStockAnalysis decoding into `response`.
+func (c *Client) getStockAnalysis(tickerID string, response interface{}) error {
 	var (
 		u, _        = url.Parse(StockInfoEndpoint + "/securities/ticker/v5/analysis/" + tickerID)
-		response    model.GetStockAnalysisResponse
 		headersMap  = make(map[string]string)
 		queryParams = make(map[string]string)
 	)
@@ -116,12 +257,7 @@ func (c *Client) GetStockAnalysis(tickerID string) (*model.GetStockAnalysisRespo
 	headersMap[HeaderKeyAccessToken] = c.AccessToken
 	headersMap[HeaderKeyDeviceID] = c.DeviceID
 
-	err := c.GetAndDecode(*u, &response, &headersMap, &queryParams)
-	if err != nil {
-		return &response, err
-	}
-
-	return &response, err
+	return c.GetAndDecode(*u, response, &headersMap, &queryParams)
 }
 
 // GetTicker gets ticker information for a provided stock symbol
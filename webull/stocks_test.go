@@ -1,8 +1,15 @@
 package webull
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	model "quantfu.com/webull/openapi"
@@ -126,3 +133,114 @@ func TestGetStockAnalysis(t *testing.T) {
 	asrt.Empty(err)
 	asrt.NotEmpty(res)
 }
+
+// quoteServer answers batch quote requests, leaving out ticker 404 and
+// failing any batch containing ticker 500.
+type quoteServer struct {
+	mu       sync.Mutex
+	batches  [][]string
+	inFlight int
+	maxSeen  int
+}
+
+func (t *quoteServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ids := strings.Split(req.URL.Query().Get("ids"), ",")
+	t.mu.Lock()
+	t.batches = append(t.batches, ids)
+	t.inFlight++
+	if t.inFlight > t.maxSeen {
+		t.maxSeen = t.inFlight
+	}
+	t.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	defer func() {
+		t.mu.Lock()
+		t.inFlight--
+		t.mu.Unlock()
+	}()
+
+	quotes := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "500" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, `{"msg":"boom","code":"500"}`)
+			return
+		}
+		if id != "404" {
+			quotes = append(quotes, `{"tickerId":`+id+`,"close":"1.5"}`)
+		}
+	}
+	_, _ = io.WriteString(w, "["+strings.Join(quotes, ",")+"]")
+}
+
+func TestGetRealtimeStockQuotesBatches(t *testing.T) {
+	asrt := assert.New(t)
+	server := &quoteServer{}
+	c := newTestClient(t, server)
+
+	ids := []int64{404, 1}
+	for i := int64(1000); i < 1000+4*quotesBatchSize; i++ {
+		ids = append(ids, i)
+	}
+	ids = append(ids, 1, 500)
+	results, err := c.GetRealtimeStockQuotes(context.Background(), ids)
+	asrt.Empty(err)
+	asrt.Len(server.batches, 5)
+	asrt.True(server.maxSeen <= maxConcurrentQuoteRequests)
+	asrt.Len(results, len(ids)-1, "duplicates are fetched once")
+
+	asrt.Empty(results[1000].Err)
+	asrt.Equal("1.5", results[1000].Quote.GetClose())
+	asrt.Error(results[404].Err)
+	asrt.Nil(results[404].Quote)
+	asrt.Error(results[500].Err)
+
+	c = newTestClient(t, &quoteServer{})
+	_, err = c.GetRealtimeStockQuotes(context.Background(), []int64{500})
+	asrt.Error(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	server = &quoteServer{}
+	c = newTestClient(t, server)
+	results, err = c.GetRealtimeStockQuotes(ctx, []int64{1000, 1001})
+	asrt.True(errors.Is(err, context.Canceled))
+	asrt.Error(results[1000].Err)
+	asrt.Len(server.batches, 0)
+
+	// cancelling while waiting for a request slot stops sending batches
+	ids = ids[:0]
+	for i := int64(1000); i < 1000+4*maxConcurrentQuoteRequests*quotesBatchSize; i++ {
+		ids = append(ids, i)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Millisecond)
+	defer cancel()
+	server = &quoteServer{}
+	c = newTestClient(t, server)
+	results, err = c.GetRealtimeStockQuotes(ctx, ids)
+	asrt.True(errors.Is(err, context.DeadlineExceeded))
+	asrt.Len(results, len(ids))
+	asrt.True(errors.Is(results[ids[len(ids)-1]].Err, context.DeadlineExceeded))
+	server.mu.Lock()
+	asrt.True(len(server.batches) < 4*maxConcurrentQuoteRequests)
+	server.mu.Unlock()
+}
+
+func TestGetRealtimeStockQuotes(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	c.RateLimit = NewRateLimiter(5, 5)
+	results, err := c.GetRealtimeStockQuotes(context.Background(), []int64{913256135, 913303964})
+	asrt.Empty(err)
+	asrt.Len(results, 2)
+}
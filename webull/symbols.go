@@ -0,0 +1,345 @@
+package webull
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	model "quantfu.com/webull/openapi"
+)
+
+// RegionUS is Webull's region ID for US listings.
+const RegionUS = 6
+
+// TickerInfo is the metadata of a listed instrument.
+type TickerInfo struct {
+	ID     int64  `json:"tickerId"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+	// Exchange is the exchange code, e.g. "NSQ", and DisplayExchange its display
+	// name, e.g. "NASDAQ". Either is accepted when resolving.
+	Exchange        string `json:"exchange"`
+	DisplayExchange string `json:"displayExchange"`
+	RegionID        int64  `json:"regionId"`
+	// Type is the instrument template, e.g. "stock" or "etf".
+	Type     string `json:"type"`
+	Currency string `json:"currency"`
+}
+
+// SymbolResolver maps symbols to ticker IDs and back, caching what it learns.
+// Unlike GetTickerID it only accepts exact symbol matches, on the requested
+// exchange or, without one, in the resolver's region.
+type SymbolResolver struct {
+	// RegionID restricts resolution without an exchange. Defaults to RegionUS.
+	RegionID int64
+	// Path, when set, is a JSON file the cache is loaded from and saved to.
+	Path string
+
+	search func(symbol string) ([]TickerInfo, error)
+	lookup func(tickerIDs []int64) (map[int64]TickerInfo, error)
+
+	mu   sync.RWMutex
+	byID map[int64]TickerInfo
+}
+
+// NewSymbolResolver is a constructor for a SymbolResolver searching with `c`.
+// When `path` is not empty, the cache is loaded from it if it exists and saved
+// back to it after each call that resolved new tickers.
+func NewSymbolResolver(c *Client, path string) (*SymbolResolver, error) {
+	r := &SymbolResolver{
+		RegionID: RegionUS,
+		Path:     path,
+		byID:     make(map[int64]TickerInfo),
+	}
+	r.search = func(symbol string) ([]TickerInfo, error) {
+		res, err := c.GetTickerV5(symbol)
+		if err != nil {
+			return nil, err
+		}
+		infos := make([]TickerInfo, 0, len(res.Data))
+		for i := range res.Data {
+			infos = append(infos, searchTickerInfo(&res.Data[i]))
+		}
+		return infos, nil
+	}
+	r.lookup = func(tickerIDs []int64) (map[int64]TickerInfo, error) {
+		quotes, err := c.GetRealtimeStockQuotes(context.Background(), tickerIDs)
+		infos := make(map[int64]TickerInfo, len(quotes))
+		for id, q := range quotes {
+			if q.Err == nil {
+				infos[id] = quoteTickerInfo(q.Quote)
+			}
+		}
+		return infos, err
+	}
+	if path != "" {
+		if err := r.Load(); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Resolve returns the ticker listed as exactly `symbol` on `exchange`, which may
+// be empty to accept any exchange in the resolver's region.
+func (r *SymbolResolver) Resolve(symbol, exchange string) (TickerInfo, error) {
+	info, added, err := r.resolve(symbol, exchange)
+	if err != nil {
+		return info, err
+	}
+	if added {
+		return info, r.Save()
+	}
+	return info, nil
+}
+
+// ResolveID returns the ticker ID of `symbol` on `exchange`, see Resolve.
+func (r *SymbolResolver) ResolveID(symbol, exchange string) (int64, error) {
+	info, err := r.Resolve(symbol, exchange)
+	return info.ID, err
+}
+
+// ResolveAll resolves every symbol in `symbols` on `exchange`, keyed by symbol
+// as given, saving the cache once at the end. Symbols that fail to resolve are
+// left out and reported in the error.
+func (r *SymbolResolver) ResolveAll(symbols []string, exchange string) (map[string]TickerInfo, error) {
+	infos := make(map[string]TickerInfo, len(symbols))
+	failed := make([]string, 0)
+	save := false
+	for _, symbol := range symbols {
+		info, added, err := r.resolve(symbol, exchange)
+		if err != nil {
+			failed = append(failed, symbol+": "+err.Error())
+			continue
+		}
+		infos[symbol] = info
+		save = save || added
+	}
+	if save {
+		if err := r.Save(); err != nil {
+			return infos, err
+		}
+	}
+	if len(failed) > 0 {
+		return infos, fmt.Errorf("could not resolve %d symbols: %s", len(failed), strings.Join(failed, "; "))
+	}
+	return infos, nil
+}
+
+// resolve is Resolve without saving, also reporting whether the cache changed.
+func (r *SymbolResolver) resolve(symbol, exchange string) (TickerInfo, bool, error) {
+	if info, ok := r.cached(symbol, exchange); ok {
+		return info, false, nil
+	}
+	candidates, err := r.search(symbol)
+	if err != nil {
+		return TickerInfo{}, false, err
+	}
+	info, ok := matchTicker(candidates, symbol, exchange, r.RegionID)
+	if !ok {
+		return TickerInfo{}, false, fmt.Errorf("no ticker found for %s", describeSymbol(symbol, exchange))
+	}
+	return info, r.add(info), nil
+}
+
+// Lookup returns the ticker with ID `tickerID`, fetching it when not cached.
+func (r *SymbolResolver) Lookup(tickerID int64) (TickerInfo, error) {
+	infos, err := r.LookupAll([]int64{tickerID})
+	if info, ok := infos[tickerID]; ok {
+		return info, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("no ticker found with id %d", tickerID)
+	}
+	return TickerInfo{}, err
+}
+
+// LookupAll returns the tickers with IDs `tickerIDs`, fetching the ones not
+// cached in a single batch.
+func (r *SymbolResolver) LookupAll(tickerIDs []int64) (map[int64]TickerInfo, error) {
+	infos := make(map[int64]TickerInfo, len(tickerIDs))
+	missing := make([]int64, 0)
+	r.mu.RLock()
+	for _, id := range tickerIDs {
+		if info, ok := r.byID[id]; ok {
+			infos[id] = info
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	r.mu.RUnlock()
+	if len(missing) == 0 {
+		return infos, nil
+	}
+
+	fetched, err := r.lookup(missing)
+	save := false
+	for id, info := range fetched {
+		if info.ID == 0 {
+			info.ID = id
+		}
+		infos[id] = info
+		save = r.add(info) || save
+	}
+	if save {
+		if saveErr := r.Save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+	return infos, err
+}
+
+// Tickers lists every cached ticker, ordered by symbol.
+func (r *SymbolResolver) Tickers() []TickerInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	infos := make([]TickerInfo, 0, len(r.byID))
+	for _, info := range r.byID {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Symbol != infos[j].Symbol {
+			return infos[i].Symbol < infos[j].Symbol
+		}
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// Load replaces the cache with the contents of Path.
+func (r *SymbolResolver) Load() error {
+	b, err := os.ReadFile(r.Path)
+	if err != nil {
+		return err
+	}
+	var infos []TickerInfo
+	if err = json.Unmarshal(b, &infos); err != nil {
+		return fmt.Errorf("invalid symbol cache %s: %s", r.Path, err.Error())
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byID = make(map[int64]TickerInfo, len(infos))
+	for _, info := range infos {
+		r.byID[info.ID] = info
+	}
+	return nil
+}
+
+// Save writes the cache to Path, replacing the file atomically.
+func (r *SymbolResolver) Save() error {
+	if r.Path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(r.Tickers(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.Path), filepath.Base(r.Path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), r.Path)
+}
+
+func (r *SymbolResolver) cached(symbol, exchange string) (TickerInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	candidates := make([]TickerInfo, 0, 1)
+	for _, info := range r.byID {
+		if strings.EqualFold(info.Symbol, symbol) {
+			candidates = append(candidates, info)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+	return matchTicker(candidates, symbol, exchange, r.RegionID)
+}
+
+// add caches `info`, reporting whether the cache changed and needs saving.
+func (r *SymbolResolver) add(info TickerInfo) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.byID[info.ID]
+	r.byID[info.ID] = info
+	return !ok || old != info
+}
+
+// matchTicker picks the candidate listed as exactly `symbol` on `exchange`, or
+// in region `regionID` when `exchange` is empty. Without an exchange, candidates
+// of unknown region never match; a zero `regionID` accepts any known region.
+func matchTicker(candidates []TickerInfo, symbol, exchange string, regionID int64) (TickerInfo, bool) {
+	for _, info := range candidates {
+		if !strings.EqualFold(info.Symbol, symbol) {
+			continue
+		}
+		if exchange != "" {
+			if strings.EqualFold(info.Exchange, exchange) || strings.EqualFold(info.DisplayExchange, exchange) {
+				return info, true
+			}
+			continue
+		}
+		if info.RegionID != 0 && (regionID == 0 || info.RegionID == regionID) {
+			return info, true
+		}
+	}
+	return TickerInfo{}, false
+}
+
+func describeSymbol(symbol, exchange string) string {
+	if exchange == "" {
+		return symbol
+	}
+	return symbol + " on " + exchange
+}
+
+// searchTickerInfo reads ticker metadata from a search result.
+func searchTickerInfo(t *model.TickerV5) TickerInfo {
+	return newTickerInfo(TickerInfo{
+		ID:              t.GetTickerId(),
+		Symbol:          t.GetSymbol(),
+		Name:            t.GetName(),
+		Exchange:        t.GetExchangeCode(),
+		DisplayExchange: t.GetDisExchangeCode(),
+		RegionID:        t.GetRegionId(),
+		Type:            t.GetTemplate(),
+		Currency:        t.GetCurrencyCode(),
+	})
+}
+
+// quoteTickerInfo reads ticker metadata from a quote.
+func quoteTickerInfo(q *model.GetStockQuoteResponse) TickerInfo {
+	return newTickerInfo(TickerInfo{
+		ID:              q.GetTickerId(),
+		Symbol:          q.GetSymbol(),
+		Name:            q.GetName(),
+		Exchange:        q.GetExchangeCode(),
+		DisplayExchange: q.GetDisExchangeCode(),
+		RegionID:        q.GetRegionId(),
+		Type:            q.GetTemplate(),
+		Currency:        q.GetCurrencyCode(),
+	})
+}
+
+// newTickerInfo fills either exchange field from the other when only one is set.
+func newTickerInfo(info TickerInfo) TickerInfo {
+	if info.Exchange == "" {
+		info.Exchange = info.DisplayExchange
+	}
+	if info.DisplayExchange == "" {
+		info.DisplayExchange = info.Exchange
+	}
+	return info
+}
@@ -0,0 +1,117 @@
+package webull
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	model "quantfu.com/webull/openapi"
+)
+
+// tickerSearchFixture is hand-written in the shape of model.TickerV5.
+const tickerSearchFixture = `[
+	{"tickerId":913254235,"symbol":"SHOP","name":"Shopify Inc","exchangeCode":"TSX","disExchangeCode":"TSX","regionId":2,"template":"stock","currencyCode":"CAD"},
+	{"tickerId":950118597,"symbol":"SHOPF","name":"Shopify Inc","exchangeCode":"OTC","disExchangeCode":"OTC","regionId":6,"template":"stock","currencyCode":"USD"},
+	{"tickerId":999999999,"symbol":"SHOP","name":"Shopify Inc","exchangeCode":"XXX","template":"stock"},
+	{"tickerId":950051491,"symbol":"SHOP","name":"Shopify Inc","exchangeCode":"NYSE","disExchangeCode":"NYSE","regionId":6,"template":"stock","currencyCode":"USD"}]`
+
+func newTestSymbolResolver(path string) (*SymbolResolver, *int) {
+	searches := 0
+	r := &SymbolResolver{RegionID: RegionUS, Path: path, byID: make(map[int64]TickerInfo)}
+	r.search = func(symbol string) ([]TickerInfo, error) {
+		searches++
+		var tickers []model.TickerV5
+		if err := json.Unmarshal([]byte(tickerSearchFixture), &tickers); err != nil {
+			return nil, err
+		}
+		infos := make([]TickerInfo, 0)
+		for i := range tickers {
+			infos = append(infos, searchTickerInfo(&tickers[i]))
+		}
+		return infos, nil
+	}
+	r.lookup = func(tickerIDs []int64) (map[int64]TickerInfo, error) {
+		infos := make(map[int64]TickerInfo)
+		for _, id := range tickerIDs {
+			if id == 913256135 {
+				infos[id] = quoteTickerInfo(&model.GetStockQuoteResponse{
+					TickerId:        model.PtrInt64(id),
+					Symbol:          model.PtrString("AAPL"),
+					ExchangeCode:    model.PtrString("NSQ"),
+					DisExchangeCode: model.PtrString("NASDAQ"),
+					RegionId:        model.PtrInt64(RegionUS),
+				})
+			}
+		}
+		return infos, nil
+	}
+	return r, &searches
+}
+
+func TestSymbolResolver(t *testing.T) {
+	asrt := assert.New(t)
+	path := filepath.Join(t.TempDir(), "symbols.json")
+	r, searches := newTestSymbolResolver(path)
+
+	info, err := r.Resolve("shop", "")
+	asrt.Empty(err)
+	asrt.Equal(int64(950051491), info.ID, "US listing, not TSX or an unknown region")
+	asrt.Equal("USD", info.Currency)
+
+	info, err = r.Resolve("SHOP", "tsx")
+	asrt.Empty(err)
+	asrt.Equal(int64(913254235), info.ID)
+	asrt.Equal(2, *searches)
+
+	id, err := r.ResolveID("SHOP", "NYSE")
+	asrt.Empty(err)
+	asrt.Equal(int64(950051491), id)
+	asrt.Equal(2, *searches, "served from cache")
+
+	_, err = r.Resolve("SHOP", "LSE")
+	asrt.Error(err)
+
+	info, err = r.Lookup(950051491)
+	asrt.Empty(err)
+	asrt.Equal("SHOP", info.Symbol)
+	info, err = r.Lookup(913256135)
+	asrt.Empty(err)
+	asrt.Equal("NASDAQ", info.DisplayExchange)
+	_, err = r.Lookup(1)
+	asrt.Error(err)
+
+	infos, err := r.ResolveAll([]string{"SHOP", "AAPL", "XYZ"}, "")
+	asrt.Error(err)
+	asrt.Len(infos, 2)
+	asrt.Equal(int64(913256135), infos["AAPL"].ID, "resolved from the reverse lookup cache")
+
+	reloaded, searches := newTestSymbolResolver(path)
+	asrt.Empty(reloaded.Load())
+	asrt.Len(reloaded.Tickers(), 3)
+	id, err = reloaded.ResolveID("SHOP", "")
+	asrt.Empty(err)
+	asrt.Equal(int64(950051491), id)
+	asrt.Equal(0, *searches)
+}
+
+func TestNewSymbolResolver(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	r, err := NewSymbolResolver(c, filepath.Join(t.TempDir(), "symbols.json"))
+	asrt.Empty(err)
+	info, err := r.Resolve("AAPL", "NASDAQ")
+	asrt.Empty(err)
+	asrt.Equal(int64(913256135), info.ID)
+}
@@ -2,47 +2,207 @@ package webull
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"strconv"
-
-	// "fmt"
-	"net/http"
+	"io"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/pkg/errors"
 	model "quantfu.com/webull/openapi"
 )
 
-// GetTransfers returns Transfers.
+// transfersPageSize is the page size used when loading every transfer.
+const transfersPageSize = 100
+
+// Transfer directions
+const (
+	TransferDeposit    = "in"
+	TransferWithdrawal = "out"
+	// TransferUnknown is the direction of a transfer whose fields match neither.
+	TransferUnknown = "unknown"
+)
+
+// TransferRecord is a single deposit or withdrawal.
+type TransferRecord struct {
+	ID         ID        `json:"id"`
+	Direction  string    `json:"direction"`
+	Amount     Number    `json:"amount"`
+	Currency   string    `json:"currency"`
+	Status     string    `json:"status"`
+	StatusName string    `json:"statusName"`
+	Type       string    `json:"type"`
+	CreateTime Timestamp `json:"createTime"`
+	// BankID is the linked bank account the money moved to or from.
+	BankID     string    `json:"achId"`
+	BankName   string    `json:"bankName"`
+	FinishTime Timestamp `json:"finishTime"`
+}
+
+// TransferFilter selects transfers by creation date, status and direction. The
+// zero value selects every transfer.
+type TransferFilter struct {
+	From time.Time
+	To   time.Time
+	// Statuses match Status or StatusName, ignoring case.
+	Statuses []string
+	// Direction is TransferDeposit or TransferWithdrawal.
+	Direction string
+}
+
+// transferList accepts either a bare list of transfers or one wrapped in `data`.
+type transferList []TransferRecord
+
+// UnmarshalJSON implements json.Unmarshaler
+func (l *transferList) UnmarshalJSON(b []byte) error {
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, (*[]TransferRecord)(l))
+	}
+	var wrapped struct {
+		Data []TransferRecord `json:"data"`
+	}
+	if err := json.Unmarshal(b, &wrapped); err != nil {
+		return err
+	}
+	*l = wrapped.Data
+	return nil
+}
+
+// GetTransfers returns the newest page of up to `count` transfers. Use
+// ListTransfers or GetTransfersPager to read every page.
 func (c *Client) GetTransfers(accountID int64, count uint32) (*model.Transfers, error) {
 	var (
 		u, _       = url.Parse(TradeEndpoint + "/asset/" + strconv.FormatInt(accountID, 10) + "/getWebullTransferList")
-		response   *model.Transfers
+		response   model.Transfers
 		headersMap = make(map[string]string)
-		// queryParams = make(map[string]string)
 	)
 
 	headersMap[HeaderKeyAccessToken] = c.AccessToken
 	headersMap[HeaderKeyDeviceID] = c.DeviceID
+	headersMap[HeaderKeyTradeToken] = c.TradeToken
+	headersMap[HeaderKeyTradeTime] = getTimeSeconds()
 
-	// Login request body
 	lrId := "0"
 	ct := float32(count)
 	request := model.GetTransfersRequest{
 		PageSize:     &ct,
 		LastRecordId: &lrId,
 	}
-	requestBody, _ := json.Marshal(request)
-	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewBuffer(requestBody))
-	req.Header.Add(HeaderKeyDeviceID, c.DeviceID)
-	req.Header.Add(HeaderKeyAccessToken, c.AccessToken)
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.PostAndDecode(*u, &response, &headersMap, nil, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListTransfers returns every transfer of account `accountID` matching
+// `filter`, newest first. Paging stops once transfers are older than filter.From.
+func (c *Client) ListTransfers(ctx context.Context, accountID int64, filter TransferFilter) ([]TransferRecord, error) {
+	pager := c.GetTransfersPager(accountID, transfersPageSize)
+	records := make([]TransferRecord, 0)
+	for {
+		r, err := pager.Next(ctx)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		if !filter.From.IsZero() && !r.CreateTime.IsZero() && r.CreateTime.Before(filter.From) {
+			return records, nil
+		}
+		if filter.matches(r) {
+			records = append(records, r)
+		}
+	}
+}
+
+// GetTransfersPager walks every page of transfers, newest first, using the
+// `lastRecordId` cursor.
+func (c *Client) GetTransfersPager(accountID int64, pageSize uint32) *Pager[TransferRecord] {
+	return NewPager(func(ctx context.Context, cursor string) ([]TransferRecord, string, error) {
+		if cursor == "" {
+			cursor = "0"
+		}
+		records, err := c.getTransfersPage(accountID, cursor, pageSize)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(records) == 0 || len(records) < int(pageSize) {
+			return records, "", nil
+		}
+		return records, string(records[len(records)-1].ID), nil
+	})
+}
+
+// getTransfersPage fetches the page of transfers following record `lastRecordID`.
+func (c *Client) getTransfersPage(accountID int64, lastRecordID string, count uint32) ([]TransferRecord, error) {
+	var (
+		u, _       = url.Parse(TradeEndpoint + "/asset/" + strconv.FormatInt(accountID, 10) + "/getWebullTransferList")
+		response   transferList
+		headersMap = make(map[string]string)
+	)
+
+	headersMap[HeaderKeyAccessToken] = c.AccessToken
+	headersMap[HeaderKeyDeviceID] = c.DeviceID
+	headersMap[HeaderKeyTradeToken] = c.TradeToken
+	headersMap[HeaderKeyTradeTime] = getTimeSeconds()
+
+	ct := float32(count)
+	request := model.GetTransfersRequest{
+		PageSize:     &ct,
+		LastRecordId: &lastRecordID,
+	}
+	payload, err := json.Marshal(request)
 	if err != nil {
-		return nil, errors.Wrap(err, "could not create request")
+		return nil, err
 	}
-	// Send and parse request
-	err = c.DoAndDecode(req, &response)
+
+	err = c.PostAndDecode(*u, &response, &headersMap, nil, payload)
 	if err != nil {
 		return nil, err
 	}
 	return response, nil
 }
+
+// TransferDirection reports whether the transfer moved money into the account
+// (TransferDeposit) or out of it (TransferWithdrawal). Values other than the
+// known ones give TransferUnknown.
+func (t TransferRecord) TransferDirection() string {
+	for _, v := range []string{t.Direction, t.Type} {
+		switch strings.ToLower(v) {
+		case TransferDeposit, "deposit":
+			return TransferDeposit
+		case TransferWithdrawal, "withdraw", "withdrawal":
+			return TransferWithdrawal
+		}
+	}
+	return TransferUnknown
+}
+
+func (f TransferFilter) matches(t TransferRecord) bool {
+	if !f.From.IsZero() && t.CreateTime.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && t.CreateTime.After(f.To) {
+		return false
+	}
+	if f.Direction != "" && !strings.EqualFold(f.Direction, t.TransferDirection()) {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, s := range f.Statuses {
+		if strings.EqualFold(s, t.Status) || strings.EqualFold(s, t.StatusName) {
+			return true
+		}
+	}
+	return false
+}
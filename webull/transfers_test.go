@@ -1,8 +1,11 @@
 package webull
 
 import (
+	"context"
+	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	model "quantfu.com/webull/openapi"
@@ -29,3 +32,49 @@ func TestGetTransfers(t *testing.T) {
 	asrt.Empty(err)
 	asrt.NotEmpty(ticker)
 }
+
+func TestTransferFilter(t *testing.T) {
+	asrt := assert.New(t)
+	var records transferList
+	asrt.Empty(json.Unmarshal([]byte(`{"data":[
+		{"id":3,"direction":"in","amount":"500","status":"SUCCESS","createTime":"2023-03-01","achId":"77"},
+		{"id":2,"direction":"out","amount":"200","status":"PENDING","createTime":"2023-02-01"},
+		{"id":1,"direction":"in","amount":"1000","status":"CANCELLED","createTime":"2022-12-01"}]}`), &records))
+	asrt.Len(records, 3)
+	asrt.Equal("77", records[0].BankID)
+	asrt.Equal(TransferDeposit, records[0].TransferDirection())
+	asrt.Equal(TransferWithdrawal, records[1].TransferDirection())
+	asrt.Equal(TransferUnknown, TransferRecord{}.TransferDirection())
+
+	asrt.True(TransferFilter{}.matches(records[2]))
+	from := TransferFilter{From: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	asrt.True(from.matches(records[1]))
+	asrt.False(from.matches(records[2]))
+	out := TransferFilter{Direction: TransferWithdrawal}
+	asrt.False(out.matches(records[0]))
+	asrt.True(out.matches(records[1]))
+	asrt.False(out.matches(TransferRecord{}))
+	status := TransferFilter{Statuses: []string{"pending", "success"}}
+	asrt.True(status.matches(records[0]))
+	asrt.False(status.matches(records[2]))
+}
+
+func TestListTransfers(t *testing.T) {
+	if os.Getenv("WEBULL_USERNAME") == "" {
+		t.Skip("No username set")
+		return
+	}
+	asrt := assert.New(t)
+	c, err := NewClient(&Credentials{
+		Username:    os.Getenv("WEBULL_USERNAME"),
+		Password:    os.Getenv("WEBULL_PASSWORD"),
+		AccountType: model.AccountType(2),
+		DeviceName:  deviceName(),
+	})
+	asrt.Empty(err)
+	accountID, err := c.GetAccountID()
+	asrt.Empty(err)
+	transfers, err := c.ListTransfers(context.Background(), accountID, TransferFilter{Direction: TransferDeposit})
+	asrt.Empty(err)
+	asrt.NotNil(transfers)
+}
@@ -3,6 +3,8 @@ package webull
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -33,6 +35,129 @@ func deviceName() string {
 	return fmt.Sprintf(os.Getenv("WEBULL_USERNAME") + "@go-client")
 }
 
+// parseFloat parses the required model value `name`. Webull returns most
+// quantities and prices as strings, sometimes with thousands separators.
+func parseFloat(name string, s *string) (float64, error) {
+	if s == nil || *s == "" {
+		return 0, fmt.Errorf("%s is missing", name)
+	}
+	return parseOptionalFloat(name, s)
+}
+
+// parseOptionalFloat is parseFloat for a value Webull may leave out, which
+// reads as zero.
+func parseOptionalFloat(name string, s *string) (float64, error) {
+	if s == nil || *s == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(*s, ",", ""), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, *s)
+	}
+	return f, nil
+}
+
+// Number is a float64 that decodes from either a JSON number or a numeric
+// string, since Webull is inconsistent about which it sends.
+type Number float64
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *Number) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" || s == "-" || s == "--" {
+		*n = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %s", string(b))
+	}
+	*n = Number(f)
+	return nil
+}
+
+// Float64 returns `n` as a float64
+func (n Number) Float64() float64 {
+	return float64(n)
+}
+
+// optionalNumber returns the value of a Number that may be left out, 0 when it is.
+func optionalNumber(n *Number) float64 {
+	if n == nil {
+		return 0
+	}
+	return float64(*n)
+}
+
+// ID is an identifier that decodes from either a JSON number or string without
+// losing precision.
+type ID string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (id *ID) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "null" {
+		s = ""
+	}
+	*id = ID(s)
+	return nil
+}
+
+// Int64 returns `id` as an int64, or 0 if it is not numeric.
+func (id ID) Int64() int64 {
+	i, _ := strconv.ParseInt(string(id), 10, 64)
+	return i
+}
+
+// Timestamp is a time that decodes from epoch milliseconds or the date formats
+// used across Webull endpoints.
+type Timestamp struct {
+	time.Time
+}
+
+var timestampLayouts = []string{
+	DefaultTokenExpiryFormat,
+	time.RFC3339,
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"01/02/2006",
+	"20060102",
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Timestamp) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" || s == "0" {
+		t.Time = time.Time{}
+		return nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil && len(s) >= 10 {
+		if len(s) == 10 {
+			t.Time = time.Unix(ms, 0)
+		} else {
+			t.Time = time.UnixMilli(ms)
+		}
+		return nil
+	}
+	for _, layout := range timestampLayouts {
+		if tm, err := time.Parse(layout, s); err == nil {
+			t.Time = tm
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid timestamp %s", string(b))
+}
+
+// optionalTime returns the time of a Timestamp that may be left out, the zero
+// time when it is.
+func optionalTime(ts *Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.Time
+}
+
 /*
 // String returns a pointer to the string value passed in.
 func String(v string) *string {
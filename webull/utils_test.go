@@ -0,0 +1,64 @@
+package webull
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeFlexibleTypes(t *testing.T) {
+	asrt := assert.New(t)
+	var v struct {
+		A Number    `json:"a"`
+		B Number    `json:"b"`
+		C ID        `json:"c"`
+		D ID        `json:"d"`
+		E Timestamp `json:"e"`
+		F Timestamp `json:"f"`
+	}
+	err := json.Unmarshal([]byte(`{"a":"1,234.5","b":2,"c":9007199254740993,"d":"abc","e":1667318400000,"f":"2022-11-01"}`), &v)
+	asrt.Empty(err)
+	asrt.Equal(Number(1234.5), v.A)
+	asrt.Equal(Number(2), v.B)
+	asrt.Equal(ID("9007199254740993"), v.C)
+	asrt.Equal(int64(9007199254740993), v.C.Int64())
+	asrt.Equal(ID("abc"), v.D)
+	asrt.True(v.E.Equal(time.Date(2022, 11, 1, 16, 0, 0, 0, time.UTC)))
+	asrt.Equal(time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC), v.F.Time)
+}
+
+func TestParseFloat(t *testing.T) {
+	asrt := assert.New(t)
+	s, thousands, empty, bad := "12.5", "1,234.5", "", "n/a"
+
+	f, err := parseFloat("price", &s)
+	asrt.Nil(err)
+	asrt.Equal(12.5, f)
+	f, err = parseFloat("price", &thousands)
+	asrt.Nil(err)
+	asrt.Equal(1234.5, f)
+	_, err = parseFloat("price", nil)
+	asrt.NotNil(err)
+	_, err = parseFloat("price", &empty)
+	asrt.NotNil(err)
+	_, err = parseFloat("price", &bad)
+	asrt.NotNil(err)
+
+	f, err = parseOptionalFloat("price", nil)
+	asrt.Nil(err)
+	asrt.Equal(0.0, f)
+	f, err = parseOptionalFloat("price", &empty)
+	asrt.Nil(err)
+	asrt.Equal(0.0, f)
+	_, err = parseOptionalFloat("price", &bad)
+	asrt.NotNil(err)
+}
+
+// testFloat parses a model amount a test expects to be present.
+func testFloat(asrt *assert.Assertions, s *string) float64 {
+	f, err := parseFloat("value", s)
+	asrt.Nil(err)
+	return f
+}
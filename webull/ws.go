@@ -253,6 +253,27 @@ type Type108Message struct {
 	Status         string `json:"status"`
 }
 
+// subscribeQuoteTopics subscribes `tickerIDs` to every message type in
+// `messageTypes` on a connected quote stream.
+func subscribeQuoteTopics(client MQTT.Client, messageTypes, tickerIDs []string) error {
+	qos := 1
+	for _, messageType := range messageTypes {
+		subscriptionReq := fmt.Sprintf(`{"tickerIds": [%v],"type": "%s"}`, strings.Join(tickerIDs, `,`), messageType)
+		if token := client.Subscribe(subscriptionReq, byte(qos), pushChannel); token.WaitTimeout(time.Second*20) && token.Error() != nil {
+			return token.Error()
+		}
+	}
+	return nil
+}
+
+// setQuoteStream records the connection ConnectStreamingQuotes is reading, so
+// ConnectStreamingOptionQuotes can subscribe on it.
+func (c *Client) setQuoteStream(client MQTT.Client) {
+	c.streamMu.Lock()
+	defer c.streamMu.Unlock()
+	c.quoteStream = client
+}
+
 // ConnectStreamingQuotes is a utility function for connecting to WS streaming API
 func (c *Client) ConnectStreamingQuotes(ctx context.Context, username, password, deviceID, accessToken string, messageTypes, tickerIDs []string) error {
 	var (
@@ -288,12 +309,11 @@ func (c *Client) ConnectStreamingQuotes(ctx context.Context, username, password,
 	if token := client.Subscribe(helloMsg, byte(qos), pushChannel); token.WaitTimeout(time.Second*60) && token.Error() != nil {
 		return token.Error()
 	}
-	for _, messageType := range messageTypes {
-		subscriptionReq := fmt.Sprintf(`{"tickerIds": [%v],"type": "%s"}`, strings.Join(tickerIDs, `,`), messageType)
-		if token := client.Subscribe(subscriptionReq, byte(qos), pushChannel); token.WaitTimeout(time.Second*20) && token.Error() != nil {
-			return token.Error()
-		}
+	if err := subscribeQuoteTopics(client, messageTypes, tickerIDs); err != nil {
+		return err
 	}
+	c.setQuoteStream(client)
+	defer c.setQuoteStream(nil)
 	for {
 		select {
 		case <-ctx.Done():
@@ -311,7 +331,7 @@ func (c *Client) ConnectStreamingQuotes(ctx context.Context, username, password,
 			select {
 			case incoming = <-bufferedMessages:
 				if callback, ok := c.WebsocketCallbacks[fmt.Sprintf("%d", incoming.Topic.Type)]; ok {
-					if err := callback(ctx, incoming.Topic, incoming.Message); err != nil {
+					if err := callback(ctx, incoming.Topic, c.streamMessage(incoming.Topic, incoming.Message)); err != nil {
 						log.Print("Error running user callback: ", err.Error())
 					}
 				} else { /* Skip, no user callback assigned yet (can change run-time) */}